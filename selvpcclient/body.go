@@ -0,0 +1,150 @@
+package selvpcclient
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseSize represents the default limit of the decoded response
+// body size in bytes.
+const DefaultMaxResponseSize = 128 << 20
+
+var (
+	// ErrResponseTooLarge is returned while reading the response body that
+	// exceeds the ServiceClient's MaxResponseSize.
+	ErrResponseTooLarge = errors.New("selvpcclient: response body is too large")
+
+	// ErrBodyConsumed is returned by the ResponseResult's RawBody and
+	// ExtractResult if the body was already decoded by the ExtractResult
+	// without buffering.
+	ErrBodyConsumed = errors.New("selvpcclient: response body is already consumed")
+)
+
+// limitedBody reads the response body and returns the ErrResponseTooLarge if
+// it contains more than limit bytes.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+// newLimitedBody wraps the body with the provided limit. The body isn't
+// limited if the limit is negative.
+func newLimitedBody(body io.ReadCloser, limit int64) io.ReadCloser {
+	if body == nil || limit < 0 {
+		return body
+	}
+
+	return &limitedBody{body: body, remaining: limit, limit: limit}
+}
+
+// Read implements the io.Reader interface.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Check if the body ends exactly at the limit.
+		var extra [1]byte
+		n, err := b.body.Read(extra[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w, the limit is %d bytes", ErrResponseTooLarge, b.limit)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// gzipBody decompresses the response body. The gzip reader is created on the
+// first read, so the gzip header isn't read for unread bodies.
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+// Read implements the io.Reader interface.
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.reader == nil {
+		b.reader, b.err = gzip.NewReader(b.body)
+		if b.err != nil {
+			return 0, b.err
+		}
+	}
+
+	return b.reader.Read(p)
+}
+
+// Close implements the io.Closer interface.
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// decompressResponse replaces the gzip encoded response body with the
+// decompressed one. It's needed only if the Accept-Encoding header is set
+// explicitly, otherwise the http.Transport decompresses the body itself.
+func decompressResponse(response *http.Response) {
+	if response.Body == nil || response.Body == http.NoBody {
+		return
+	}
+	if !strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	response.Body = &gzipBody{body: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+}
+
+// DecodeJSONArray decodes elements of the array with the provided key of the
+// top-level JSON object one by one with the decodeElement function, so only
+// the current element is buffered by the decoder. Other keys are skipped.
+// It can be used to implement the StreamUnmarshaler of list responses.
+func DecodeJSONArray(decoder *json.Decoder, key string, decodeElement func() error) error {
+	found, err := seekJSONArray(decoder, key)
+	if err != nil {
+		return err
+	}
+	if found {
+		for decoder.More() {
+			if err := decodeElement(); err != nil {
+				return err
+			}
+		}
+		// Consume the closing bracket of the array.
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+
+	// Skip the rest of the object.
+	for decoder.More() {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+
+	return err
+}
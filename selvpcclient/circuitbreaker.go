@@ -0,0 +1,249 @@
+package selvpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCircuitFailureThreshold represents the default number of
+	// consecutive failures that opens the circuit.
+	defaultCircuitFailureThreshold = 5
+
+	// defaultCircuitOpenTimeout represents the default period of time during
+	// which requests are rejected before the circuit becomes half-open.
+	defaultCircuitOpenTimeout = 30 * time.Second
+
+	// defaultCircuitHalfOpenRequests represents the default number of trial
+	// requests in the half-open state.
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned by the ServiceClient's DoRequest without sending
+// the request when its CircuitBreaker doesn't allow requests.
+// It can be checked with errors.Is.
+var ErrCircuitOpen = errors.New("selvpcclient: circuit breaker is open")
+
+// CircuitState represents a state of the CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed allows all requests.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects all requests with the ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen allows a limited number of trial requests to check if
+	// the endpoint has recovered.
+	CircuitHalfOpen
+)
+
+// String implements the fmt.Stringer interface.
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(state))
+}
+
+// CircuitBreaker stops sending requests to a degraded endpoint.
+// The circuit is opened after FailureThreshold consecutive failures and all
+// requests fail fast with the ErrCircuitOpen. After the OpenTimeout the circuit
+// becomes half-open and lets HalfOpenRequests trial requests through. The
+// circuit is closed if all of them succeed and opened again on any failure.
+// Transport errors and 5xx responses are counted as failures.
+// It's safe for concurrent use and can be shared between several clients.
+type CircuitBreaker struct {
+	// FailureThreshold represents the number of consecutive failures that
+	// opens the circuit. Default value is used if it's less than 1.
+	FailureThreshold int
+
+	// OpenTimeout represents the period of time during which requests are
+	// rejected before the circuit becomes half-open.
+	// Default value is used if it's not positive.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests represents the number of trial requests in the
+	// half-open state. Default value is used if it's less than 1.
+	HalfOpenRequests int
+
+	// OnStateChange is called every time the circuit changes its state.
+	OnStateChange func(from, to CircuitState)
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	trials    int
+	successes int
+
+	// generation is increased on every state change, so results of requests
+	// that were allowed in a previous state are ignored.
+	generation uint64
+}
+
+// NewCircuitBreaker returns a reference to the CircuitBreaker that opens after
+// the provided number of consecutive failures for the provided period of time.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+	}
+}
+
+// State returns the current state of the circuit.
+func (breaker *CircuitBreaker) State() CircuitState {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.refresh(time.Now())
+
+	return breaker.state
+}
+
+// Allow checks if a request can be sent. It returns the generation that must
+// be passed to the Done method together with the request result.
+func (breaker *CircuitBreaker) Allow() (uint64, error) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	now := time.Now()
+	breaker.refresh(now)
+
+	switch breaker.state {
+	case CircuitOpen:
+		retryIn := breaker.openTimeout() - now.Sub(breaker.openedAt)
+		return 0, fmt.Errorf("%w: retry in %s", ErrCircuitOpen, retryIn.Round(time.Millisecond))
+	case CircuitHalfOpen:
+		if breaker.trials >= breaker.halfOpenRequests() {
+			return 0, fmt.Errorf("%w: waiting for the trial requests", ErrCircuitOpen)
+		}
+		breaker.trials++
+	}
+
+	return breaker.generation, nil
+}
+
+// Done reports the result of the request that was allowed by the Allow method.
+func (breaker *CircuitBreaker) Done(generation uint64, response *http.Response, err error) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if generation != breaker.generation {
+		return
+	}
+
+	// Canceled requests say nothing about the endpoint health.
+	if err != nil && errors.Is(err, context.Canceled) {
+		if breaker.state == CircuitHalfOpen {
+			breaker.trials--
+		}
+		return
+	}
+
+	if isCircuitFailure(response, err) {
+		breaker.failures++
+		if breaker.state == CircuitHalfOpen || breaker.failures >= breaker.failureThreshold() {
+			breaker.setState(CircuitOpen, time.Now())
+		}
+		return
+	}
+
+	breaker.failures = 0
+	if breaker.state == CircuitHalfOpen {
+		breaker.successes++
+		if breaker.successes >= breaker.halfOpenRequests() {
+			breaker.setState(CircuitClosed, time.Now())
+		}
+	}
+}
+
+// refresh moves the open circuit to the half-open state after the OpenTimeout.
+func (breaker *CircuitBreaker) refresh(now time.Time) {
+	if breaker.state == CircuitOpen && now.Sub(breaker.openedAt) >= breaker.openTimeout() {
+		breaker.setState(CircuitHalfOpen, now)
+	}
+}
+
+// setState changes the state of the circuit and resets its counters.
+func (breaker *CircuitBreaker) setState(state CircuitState, now time.Time) {
+	from := breaker.state
+	breaker.state = state
+	breaker.generation++
+	breaker.failures = 0
+	breaker.trials = 0
+	breaker.successes = 0
+	if state == CircuitOpen {
+		breaker.openedAt = now
+	}
+
+	if breaker.OnStateChange != nil && from != state {
+		breaker.OnStateChange(from, state)
+	}
+}
+
+// failureThreshold returns the FailureThreshold or its default value.
+func (breaker *CircuitBreaker) failureThreshold() int {
+	if breaker.FailureThreshold < 1 {
+		return defaultCircuitFailureThreshold
+	}
+
+	return breaker.FailureThreshold
+}
+
+// openTimeout returns the OpenTimeout or its default value.
+func (breaker *CircuitBreaker) openTimeout() time.Duration {
+	if breaker.OpenTimeout <= 0 {
+		return defaultCircuitOpenTimeout
+	}
+
+	return breaker.OpenTimeout
+}
+
+// halfOpenRequests returns the HalfOpenRequests or its default value.
+func (breaker *CircuitBreaker) halfOpenRequests() int {
+	if breaker.HalfOpenRequests < 1 {
+		return defaultCircuitHalfOpenRequests
+	}
+
+	return breaker.HalfOpenRequests
+}
+
+// isCircuitFailure checks if the result of a single attempt shows that the
+// endpoint is degraded.
+func isCircuitFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return response.StatusCode >= 500
+}
+
+// allowCircuit checks the ServiceClient's CircuitBreaker if it's set.
+func (client *ServiceClient) allowCircuit() (uint64, error) {
+	if client.CircuitBreaker == nil {
+		return 0, nil
+	}
+
+	return client.CircuitBreaker.Allow()
+}
+
+// doneCircuit reports the attempt result to the ServiceClient's CircuitBreaker
+// if it's set.
+func (client *ServiceClient) doneCircuit(generation uint64, response *http.Response, err error) {
+	if client.CircuitBreaker == nil {
+		return
+	}
+	client.CircuitBreaker.Done(generation, response, err)
+}
@@ -0,0 +1,315 @@
+package selvpcclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvToken contains the name of the environment variable with the
+	// authentication token.
+	EnvToken = "SELVPC_TOKEN"
+
+	// EnvEndpoint contains the name of the environment variable with the
+	// API endpoint.
+	EnvEndpoint = "SELVPC_ENDPOINT"
+
+	// EnvTimeout contains the name of the environment variable with the HTTP
+	// request timeout. It's either a duration like "30s" or a number of
+	// seconds.
+	EnvTimeout = "SELVPC_TIMEOUT"
+
+	// EnvRetries contains the name of the environment variable with the
+	// number of retries of failed requests.
+	EnvRetries = "SELVPC_RETRIES"
+
+	// EnvProfile contains the name of the environment variable with the
+	// name of the profile to use.
+	EnvProfile = "SELVPC_PROFILE"
+
+	// EnvConfigFile contains the name of the environment variable with the
+	// path to the profile file.
+	EnvConfigFile = "SELVPC_CONFIG_FILE"
+
+	// DefaultProfile contains the name of the profile that is used if no
+	// profile is selected.
+	DefaultProfile = "default"
+)
+
+var (
+	errConfigEmptyToken      = errors.New("token is empty")
+	errConfigInvalidEndpoint = errors.New("absolute http or https URL is required")
+	errConfigInvalidTimeout  = errors.New("timeout must be positive")
+	errConfigInvalidRetries  = errors.New("retries can't be negative")
+	errConfigUnknownProfile  = errors.New("profile is not found")
+	errConfigEnvEndpoint     = errors.New(EnvEndpoint + " requires " + EnvToken)
+)
+
+// Config contains parameters of the client that can be loaded from the
+// environment and the profile file with the LoadConfig.
+type Config struct {
+	// Token contains the authentication token.
+	Token string
+
+	// Endpoint contains the API endpoint.
+	// Default endpoint of the client is used if it's empty.
+	Endpoint string
+
+	// Timeout contains the timeout of the whole HTTP request.
+	// Default timeout of the client is used if it's zero.
+	Timeout time.Duration
+
+	// Retries contains the number of retries of failed requests.
+	// Requests aren't retried if it's zero.
+	Retries int
+
+	// Profile contains the name of the used profile.
+	// It's empty if the profile file wasn't used.
+	Profile string
+}
+
+// Validate checks that the Config can be used to build a client.
+func (cfg *Config) Validate() error {
+	if cfg.Token == "" {
+		return fmt.Errorf("selvpcclient: invalid config: %w", errConfigEmptyToken)
+	}
+	if cfg.Endpoint != "" {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return fmt.Errorf("selvpcclient: invalid config endpoint %q: %w", cfg.Endpoint, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("selvpcclient: invalid config endpoint %q: %w", cfg.Endpoint, errConfigInvalidEndpoint)
+		}
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("selvpcclient: invalid config timeout %s: %w", cfg.Timeout, errConfigInvalidTimeout)
+	}
+	if cfg.Retries < 0 {
+		return fmt.Errorf("selvpcclient: invalid config retries %d: %w", cfg.Retries, errConfigInvalidRetries)
+	}
+
+	return nil
+}
+
+// RetryPolicy returns the default retry policy with the configured number of
+// retries. It returns nil if retries are disabled.
+func (cfg *Config) RetryPolicy() *RetryPolicy {
+	if cfg.Retries == 0 {
+		return nil
+	}
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = cfg.Retries + 1
+
+	return policy
+}
+
+// LoadConfigOpts represents options for the LoadConfig.
+type LoadConfigOpts struct {
+	// Profile contains the name of the profile to use.
+	// The SELVPC_PROFILE variable, the default_profile from the file or the
+	// DefaultProfile are used if it's empty.
+	Profile string
+
+	// Path contains the path to the profile file.
+	// The SELVPC_CONFIG_FILE variable or the DefaultConfigPath are used if
+	// it's empty. Only the default file is allowed to be absent.
+	Path string
+}
+
+// ProfileFile represents the JSON profile file with multiple named accounts:
+//
+//	{
+//	  "default_profile": "main",
+//	  "profiles": {
+//	    "main": {"token": "...", "timeout": "30s", "retries": 2},
+//	    "staging": {"token": "...", "endpoint": "https://example.org/resell/v2"}
+//	  }
+//	}
+type ProfileFile struct {
+	// DefaultProfile contains the name of the profile that is used if no
+	// profile is selected.
+	DefaultProfile string `json:"default_profile"`
+
+	// Profiles contains profiles by their names.
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Profile represents a single account in the profile file.
+type Profile struct {
+	// Token contains the authentication token.
+	Token string `json:"token"`
+
+	// Endpoint contains the API endpoint.
+	Endpoint string `json:"endpoint"`
+
+	// Timeout contains the HTTP request timeout like "30s".
+	Timeout string `json:"timeout"`
+
+	// Retries contains the number of retries of failed requests.
+	Retries *int `json:"retries"`
+}
+
+// DefaultConfigPath returns the path of the default profile file that is
+// located in the user's home directory.
+func DefaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".selvpc", "config.json"), nil
+}
+
+// LoadConfig loads and validates the client config. Values are taken with the
+// following precedence, from the highest to the lowest:
+//
+//  1. SELVPC_TOKEN, SELVPC_ENDPOINT, SELVPC_TIMEOUT and SELVPC_RETRIES
+//     environment variables;
+//  2. the selected profile from the profile file;
+//  3. defaults of the client.
+//
+// The token and the endpoint are always taken from the same source, so the
+// SELVPC_TOKEN replaces the profile endpoint with the SELVPC_ENDPOINT or
+// the default one, and the SELVPC_ENDPOINT can't be used without the
+// SELVPC_TOKEN.
+// The precedence doesn't depend on how the profile is selected, so the
+// environment variables override both the opts.Profile and the
+// SELVPC_PROFILE profiles. An error is returned if the selected profile
+// can't be loaded.
+func LoadConfig(opts LoadConfigOpts) (*Config, error) {
+	cfg := &Config{}
+
+	if err := cfg.loadProfile(opts); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadProfile populates the Config from the selected profile of the profile
+// file.
+func (cfg *Config) loadProfile(opts LoadConfigOpts) error {
+	profileName := opts.Profile
+	if profileName == "" {
+		profileName = os.Getenv(EnvProfile)
+	}
+
+	path, required := opts.Path, true
+	if path == "" {
+		path = os.Getenv(EnvConfigFile)
+	}
+	if path == "" {
+		defaultPath, err := DefaultConfigPath()
+		if err != nil {
+			if profileName != "" {
+				return fmt.Errorf("selvpcclient: unable to load the %s profile: %w", profileName, err)
+			}
+			return nil
+		}
+		path, required = defaultPath, false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			if profileName != "" {
+				return fmt.Errorf("selvpcclient: %w: %s", errConfigUnknownProfile, profileName)
+			}
+			return nil
+		}
+		return fmt.Errorf("selvpcclient: unable to read the profile file: %w", err)
+	}
+
+	var file ProfileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("selvpcclient: unable to parse the %s profile file: %w", path, err)
+	}
+
+	explicit := profileName != ""
+	if profileName == "" {
+		profileName = file.DefaultProfile
+	}
+	if profileName == "" {
+		profileName = DefaultProfile
+	}
+	profile, ok := file.Profiles[profileName]
+	if !ok {
+		// Missing implicit default profile isn't an error, so the file can
+		// contain only named profiles.
+		if !explicit && file.DefaultProfile == "" {
+			return nil
+		}
+		return fmt.Errorf("selvpcclient: %w in the %s file: %s", errConfigUnknownProfile, path, profileName)
+	}
+
+	cfg.Profile = profileName
+	cfg.Token = profile.Token
+	cfg.Endpoint = profile.Endpoint
+	if profile.Timeout != "" {
+		timeout, err := parseConfigTimeout(profile.Timeout)
+		if err != nil {
+			return fmt.Errorf("selvpcclient: invalid timeout of the %s profile: %w", profileName, err)
+		}
+		cfg.Timeout = timeout
+	}
+	if profile.Retries != nil {
+		cfg.Retries = *profile.Retries
+	}
+
+	return nil
+}
+
+// loadEnv overrides the Config values with the environment variables.
+func (cfg *Config) loadEnv() error {
+	token, endpoint := os.Getenv(EnvToken), os.Getenv(EnvEndpoint)
+	if token == "" && endpoint != "" {
+		return fmt.Errorf("selvpcclient: invalid config: %w", errConfigEnvEndpoint)
+	}
+	if token != "" {
+		// Don't send the environment token to the endpoint of the profile.
+		cfg.Token = token
+		cfg.Endpoint = endpoint
+	}
+	if value := os.Getenv(EnvTimeout); value != "" {
+		timeout, err := parseConfigTimeout(value)
+		if err != nil {
+			return fmt.Errorf("selvpcclient: invalid %s: %w", EnvTimeout, err)
+		}
+		cfg.Timeout = timeout
+	}
+	if value := os.Getenv(EnvRetries); value != "" {
+		retries, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("selvpcclient: invalid %s: %w", EnvRetries, err)
+		}
+		cfg.Retries = retries
+	}
+
+	return nil
+}
+
+// parseConfigTimeout parses either a duration like "30s" or a number of
+// seconds.
+func parseConfigTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
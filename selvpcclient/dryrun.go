@@ -0,0 +1,222 @@
+package selvpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrDryRun is returned while extracting the result of the request that was
+// recorded in the dry-run mode instead of being sent. Functions that return
+// created or updated resources return it with a nil resource and the synthetic
+// ResponseResult, so callers can distinguish planned mutations from responses
+// with no resource.
+var ErrDryRun = errors.New("selvpcclient: request was recorded in the dry-run mode and wasn't sent")
+
+// dryRunResources contains resources whose mutations are recorded instead of
+// being sent in the dry-run mode.
+var dryRunResources = map[string]struct{}{
+	"projects":     {},
+	"quotas":       {},
+	"users":        {},
+	"roles":        {},
+	"keypairs":     {},
+	"floatingips":  {},
+	"subnets":      {},
+	"licenses":     {},
+	"vrrp_subnets": {},
+}
+
+// PlannedOperation represents a single mutation that was recorded in the
+// dry-run mode.
+type PlannedOperation struct {
+	// Resource contains the name of the resource like "projects".
+	Resource string `json:"resource"`
+
+	// Name contains the kind of the mutation like "create" or "delete".
+	Name string `json:"operation"`
+
+	// Method contains the HTTP method of the request.
+	Method string `json:"method"`
+
+	// URL contains the URL of the request.
+	URL string `json:"url"`
+
+	// Body contains the original JSON body of the request where only values
+	// of the secret fields are redacted.
+	// It's empty if the request has no body.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// String returns the operation in the "resource.name METHOD URL body" form.
+func (operation PlannedOperation) String() string {
+	s := fmt.Sprintf("%s.%s %s %s", operation.Resource, operation.Name, operation.Method, operation.URL)
+	if len(operation.Body) > 0 {
+		s += " " + string(operation.Body)
+	}
+
+	return s
+}
+
+// DryRunPlan records mutations of the ServiceClient in the dry-run mode.
+// It's safe for concurrent use.
+type DryRunPlan struct {
+	mu         sync.Mutex
+	operations []PlannedOperation
+}
+
+// NewDryRunPlan returns a reference to the empty DryRunPlan.
+func NewDryRunPlan() *DryRunPlan {
+	return &DryRunPlan{}
+}
+
+// Operations returns a copy of the recorded operations in the order they were
+// requested.
+func (plan *DryRunPlan) Operations() []PlannedOperation {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	operations := make([]PlannedOperation, len(plan.operations))
+	copy(operations, plan.operations)
+
+	return operations
+}
+
+// Reset removes all recorded operations.
+func (plan *DryRunPlan) Reset() {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	plan.operations = nil
+}
+
+// String returns the numbered list of the recorded operations, one per line.
+func (plan *DryRunPlan) String() string {
+	var builder strings.Builder
+	for i, operation := range plan.Operations() {
+		fmt.Fprintf(&builder, "%d. %s\n", i+1, operation)
+	}
+
+	return builder.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (plan *DryRunPlan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Operations []PlannedOperation `json:"operations"`
+	}{
+		Operations: plan.Operations(),
+	})
+}
+
+// record appends the operation to the plan.
+func (plan *DryRunPlan) record(operation PlannedOperation) {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	plan.operations = append(plan.operations, operation)
+}
+
+// DryRunMiddleware returns a middleware that records mutations of the known
+// resources to the provided plan instead of sending them. Recorded requests
+// get synthetic successful responses with the DryRun flag: an empty JSON
+// object for creations and updates and no content for deletions.
+// Other requests are sent as usual.
+func DryRunMiddleware(plan *DryRunPlan, endpoint string) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			switch request.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(request)
+			}
+			operation := OperationFromRequest(endpoint, request)
+			if _, ok := dryRunResources[operation.Resource]; !ok {
+				return next(request)
+			}
+
+			plannedOperation := PlannedOperation{
+				Resource: operation.Resource,
+				Name:     operation.Name,
+				Method:   request.Method,
+				URL:      request.URL.String(),
+			}
+			if request.GetBody != nil {
+				body, err := request.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				requestBody, err := ioutil.ReadAll(body)
+				body.Close()
+				if err != nil {
+					return nil, err
+				}
+				plannedOperation.Body = dryRunBody(requestBody)
+			}
+			plan.record(plannedOperation)
+
+			return newDryRunResult(request), nil
+		}
+	}
+}
+
+// dryRunBody returns the request body with redacted secrets that can be
+// marshalled as a part of the plan.
+func dryRunBody(body []byte) json.RawMessage {
+	redacted := redactBody(body)
+	if redacted == "" {
+		return nil
+	}
+	if json.Valid([]byte(redacted)) {
+		return json.RawMessage(redacted)
+	}
+	quoted, _ := json.Marshal(redacted)
+
+	return quoted
+}
+
+// newDryRunResult returns a synthetic successful result for the recorded
+// request. Its ExtractResult returns the ErrDryRun.
+func newDryRunResult(request *http.Request) *ResponseResult {
+	statusCode, body := http.StatusOK, []byte("{}")
+	if request.Method == http.MethodDelete {
+		statusCode, body = http.StatusNoContent, nil
+	}
+
+	header := make(http.Header)
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+	}
+
+	return &ResponseResult{
+		Response: &http.Response{
+			Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode:    statusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       request,
+		},
+		Method: request.Method,
+		URL:    request.URL.String(),
+		DryRun: true,
+	}
+}
+
+// dryRunMiddlewares returns the DryRunMiddleware if the ServiceClient's
+// DryRun plan is set. It's applied after all other middlewares so the
+// recorded requests are logged, traced and measured as usual.
+func (client *ServiceClient) dryRunMiddlewares() []Middleware {
+	if client.DryRun == nil {
+		return nil
+	}
+
+	return []Middleware{DryRunMiddleware(client.DryRun, client.Endpoint)}
+}
@@ -0,0 +1,230 @@
+package selvpcclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// quotaErrorMarker is a substring of the error code or message that is used by
+// the Resell API to report about exceeded quotas.
+const quotaErrorMarker = "quota"
+
+// quotaExceededCodes contains known error codes of exceeded quotas.
+var quotaExceededCodes = []string{
+	"quota_exceeded",
+	"quotaexceeded",
+	"over_quota",
+	"overquota",
+}
+
+// quotaExceededStatusCodes contains status codes that are checked for the
+// quota marker in the error code and message if the code isn't known.
+var quotaExceededStatusCodes = []int{
+	http.StatusForbidden,
+	http.StatusConflict,
+	http.StatusUnprocessableEntity,
+}
+
+// APIError represents an unsuccessful response of the Selectel VPC API.
+// It is returned in the ResponseResult.Err field and by every resource
+// function, so it can be retrieved with errors.As.
+type APIError struct {
+	// StatusCode contains the HTTP status code of the response.
+	StatusCode int
+
+	// Method contains the HTTP method of the failed request.
+	Method string
+
+	// URL contains the URL of the failed request.
+	URL string
+
+	// Body contains the raw response body.
+	Body []byte
+
+	// Code contains an error code that was parsed from the response body.
+	// It's empty if the body has no known error structure.
+	Code string
+
+	// Message contains a human-readable error description that was parsed
+	// from the response body.
+	// It's empty if the body has no known error structure.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	body := compactBody(e.Body)
+	if body == "" {
+		return fmt.Sprintf("selvpcclient: got the %d %s", e.StatusCode, errServiceResponse)
+	}
+
+	return fmt.Sprintf("selvpcclient: got the %d %s: %s", e.StatusCode, errServiceResponse, body)
+}
+
+// Unwrap returns the base service response error.
+func (e *APIError) Unwrap() error {
+	return errServiceResponse
+}
+
+// IsNotFound checks if provided error reports that the requested resource
+// doesn't exist.
+// It can be used to treat already deleted resources as a success.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict checks if provided error reports about a conflict with the
+// current state of the resource.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsUnauthorized checks if provided error reports that the used token is
+// invalid or expired.
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsQuotaExceeded checks if provided error reports that the request can't be
+// fulfilled because of insufficient quotas.
+// Any 4xx error except 404 with a known quota error code is matched. Errors
+// with 403, 409 and 422 status codes are also matched if their parsed code or
+// message mentions quotas. The raw body isn't checked, so validation errors
+// that echo quota values aren't matched.
+func IsQuotaExceeded(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode < 400 || apiErr.StatusCode > 499 || apiErr.StatusCode == http.StatusNotFound {
+		return false
+	}
+
+	code := strings.ToLower(apiErr.Code)
+	for _, quotaCode := range quotaExceededCodes {
+		if code == quotaCode {
+			return true
+		}
+	}
+
+	for _, statusCode := range quotaExceededStatusCodes {
+		if apiErr.StatusCode != statusCode {
+			continue
+		}
+		for _, s := range []string{code, apiErr.Message} {
+			if strings.Contains(strings.ToLower(s), quotaErrorMarker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasStatusCode checks if provided error is the APIError with the specified
+// status code.
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == statusCode
+}
+
+// newAPIError builds APIError from the response parameters and parses
+// the known error fields from the body.
+func newAPIError(statusCode int, method, url string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		URL:        url,
+		Body:       body,
+	}
+	apiErr.Code, apiErr.Message = parseErrorBody(body)
+
+	return apiErr
+}
+
+// parseErrorBody tries to extract error code and message from the response body.
+// There are no strict error definition in the API, so several known variants
+// are checked:
+//
+//	{"error": "quota_exceeded"}
+//	{"error": {"code": "quota_exceeded", "message": "..."}}
+//	{"code": "quota_exceeded", "message": "..."}
+func parseErrorBody(body []byte) (string, string) {
+	var s struct {
+		Error   json.RawMessage `json:"error"`
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(body, &s); err != nil {
+		return "", ""
+	}
+
+	code, message := rawToString(s.Code), s.Message
+	if len(s.Error) == 0 {
+		return code, message
+	}
+
+	var errString string
+	if err := json.Unmarshal(s.Error, &errString); err == nil {
+		return errString, message
+	}
+
+	var nested struct {
+		Code    json.RawMessage `json:"code"`
+		Title   string          `json:"title"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(s.Error, &nested); err != nil {
+		return code, message
+	}
+	if nestedCode := rawToString(nested.Code); nestedCode != "" {
+		code = nestedCode
+	} else if nested.Title != "" {
+		code = nested.Title
+	}
+	if nested.Message != "" {
+		message = nested.Message
+	}
+
+	return code, message
+}
+
+// rawToString converts raw JSON string or number to the Go string.
+func rawToString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+
+	return ""
+}
+
+// compactBody builds a string without whitespaces from the response body.
+func compactBody(body []byte) string {
+	var builder strings.Builder
+	builder.Grow(len(body))
+	for _, ch := range string(body) {
+		if !unicode.IsSpace(ch) {
+			builder.WriteRune(ch)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,106 @@
+package selvpcclient
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+)
+
+// IdempotencyKeyHeader contains the name of the header that is used to send
+// idempotency keys.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyContextKey is used to store idempotency keys in contexts.
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of the context with the provided
+// idempotency key. Requests that are sent with this context have the
+// Idempotency-Key header and can be retried even if they aren't idempotent.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKey returns the idempotency key from the context.
+// It returns an empty string if the context has no key.
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// EnsureIdempotencyKey returns the context with a new random idempotency key
+// if the provided context has no key yet.
+func EnsureIdempotencyKey(ctx context.Context) context.Context {
+	if IdempotencyKey(ctx) != "" {
+		return ctx
+	}
+
+	return WithIdempotencyKey(ctx, NewIdempotencyKey())
+}
+
+// NewIdempotencyKey returns a new random idempotency key in the UUID format.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("selvpcclient: unable to read random bytes: %v", err))
+	}
+	// Set version 4 and RFC 4122 variant bits.
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// IdempotencyKeyMiddleware returns a middleware that sets the
+// Idempotency-Key header if the request's context contains a key.
+func IdempotencyKeyMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			if key := IdempotencyKey(request.Context()); key != "" {
+				request.Header.Set(IdempotencyKeyHeader, key)
+			}
+			return next(request)
+		}
+	}
+}
+
+// IsAmbiguousError checks if provided error leaves it unknown whether the
+// server has processed the request. It's true for transport errors like
+// timeouts and for the 5xx API errors. Errors of DNS lookups and refused
+// connections aren't ambiguous because the request wasn't sent.
+func IsAmbiguousError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return !isNotSentError(urlErr)
+	}
+
+	return errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, errRateLimitExceedsDeadline)
+}
+
+// isNotSentError checks if the transport error happened before the request
+// was sent, like a DNS lookup or a connection establishment failure.
+func isNotSentError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
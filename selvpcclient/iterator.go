@@ -0,0 +1,281 @@
+package selvpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	errIteratorNilFetch   = errors.New("iterator fetch function is nil")
+	errIteratorNilNew     = errors.New("iterator new function is nil")
+	errIteratorNotObject  = errors.New("response body isn't a JSON object")
+	errIteratorNotArray   = errors.New("response value isn't a JSON array")
+	errIteratorNilResult  = errors.New("iterator fetch function returned no result")
+	errIteratorMarkerLoop = errors.New("next page marker isn't changed")
+)
+
+// ListIteratorOpts represents options of the ListIterator.
+type ListIteratorOpts struct {
+	// Fetch requests the page that starts after the provided marker.
+	// The marker is empty for the first page.
+	Fetch func(ctx context.Context, marker string) (*ResponseResult, error)
+
+	// Key contains the key of the JSON array in the response body like
+	// "floatingips".
+	Key string
+
+	// New returns a pointer to the new element that an array value is
+	// decoded into.
+	New func() interface{}
+
+	// Marker returns the marker of the next page from the last element of
+	// the page. Only the first page is requested if it's nil.
+	Marker func(value interface{}) string
+
+	// Limit contains the requested page size. The next page is requested only
+	// if the current page contains exactly Limit elements, so a server that
+	// ignores the limit and returns everything is requested once.
+	Limit int
+
+	// Filter reports whether the element should be returned. Elements that
+	// don't match are skipped, but still counted for the page size and used
+	// for the marker. All elements are returned if it's nil.
+	Filter func(value interface{}) bool
+}
+
+// FetchURL returns the ListIteratorOpts's Fetch function that sends GET
+// requests with the provided client to URLs that are built for page markers.
+func FetchURL(client *ServiceClient, buildURL func(marker string) (string, error)) func(ctx context.Context, marker string) (*ResponseResult, error) {
+	return func(ctx context.Context, marker string) (*ResponseResult, error) {
+		url, err := buildURL(marker)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.DoRequest(ctx, http.MethodGet, url, nil)
+	}
+}
+
+// ListIterator iterates over elements of list responses page by page.
+// Elements are decoded from the response body one by one, so only the
+// current element is kept in memory. Resource packages embed it into their
+// Iterator types that return typed values.
+//
+//	for iterator.Next(ctx) {
+//		fmt.Println(iterator.Value())
+//	}
+//	if err := iterator.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type ListIterator struct {
+	opts ListIteratorOpts
+
+	result  *ResponseResult
+	decoder *json.Decoder
+	value   interface{}
+	last    interface{}
+	count   int
+	marker  string
+	done    bool
+	err     error
+}
+
+// NewListIterator returns a reference to the ListIterator with the provided
+// options.
+func NewListIterator(opts ListIteratorOpts) *ListIterator {
+	iterator := &ListIterator{opts: opts}
+	switch {
+	case opts.Fetch == nil:
+		iterator.err = fmt.Errorf("selvpcclient: %w", errIteratorNilFetch)
+	case opts.New == nil:
+		iterator.err = fmt.Errorf("selvpcclient: %w", errIteratorNilNew)
+	}
+
+	return iterator
+}
+
+// Next advances the iterator to the next element and requests the next page
+// if it's needed. It returns false when there are no more elements or an
+// error occurred, use the Err method to distinguish these cases.
+func (iterator *ListIterator) Next(ctx context.Context) bool {
+	for {
+		if iterator.done || iterator.err != nil {
+			return false
+		}
+		if iterator.decoder == nil {
+			iterator.fetchPage(ctx)
+			continue
+		}
+
+		if iterator.decoder.More() {
+			value := iterator.opts.New()
+			if err := iterator.decoder.Decode(value); err != nil {
+				iterator.fail(err)
+				return false
+			}
+			iterator.last = value
+			iterator.count++
+			if iterator.opts.Filter != nil && !iterator.opts.Filter(value) {
+				continue
+			}
+			iterator.value = value
+			return true
+		}
+
+		// Consume the closing bracket of the array to check the body.
+		if _, err := iterator.decoder.Token(); err != nil {
+			iterator.fail(err)
+			return false
+		}
+		iterator.nextPage()
+	}
+}
+
+// Value returns the current element. It's a pointer that was returned by
+// the New function of the options.
+func (iterator *ListIterator) Value() interface{} {
+	return iterator.value
+}
+
+// Err returns the error that stopped the iteration.
+func (iterator *ListIterator) Err() error {
+	return iterator.err
+}
+
+// Result returns the ResponseResult of the current page.
+// It's nil until the first page is requested.
+func (iterator *ListIterator) Result() *ResponseResult {
+	return iterator.result
+}
+
+// Close stops the iteration and releases the response body of the current
+// page. It should be called if the iteration is stopped before Next returns
+// false.
+func (iterator *ListIterator) Close() error {
+	iterator.done = true
+
+	return iterator.closeBody()
+}
+
+// fetchPage requests the next page and positions the decoder at the first
+// element of the array.
+func (iterator *ListIterator) fetchPage(ctx context.Context) {
+	result, err := iterator.opts.Fetch(ctx, iterator.marker)
+	if err != nil {
+		iterator.fail(err)
+		return
+	}
+	if result == nil {
+		iterator.fail(fmt.Errorf("selvpcclient: %w", errIteratorNilResult))
+		return
+	}
+	iterator.result = result
+	if result.Err != nil {
+		iterator.fail(result.Err)
+		return
+	}
+	if result.Body == nil {
+		iterator.done = true
+		return
+	}
+
+	decoder := json.NewDecoder(result.Body)
+	found, err := seekJSONArray(decoder, iterator.opts.Key)
+	if err != nil {
+		iterator.fail(err)
+		return
+	}
+	if !found {
+		iterator.done = true
+		iterator.closeBody()
+		return
+	}
+	iterator.decoder = decoder
+	iterator.count = 0
+}
+
+// nextPage closes the current page and prepares the marker of the next one.
+func (iterator *ListIterator) nextPage() {
+	iterator.closeBody()
+	iterator.decoder = nil
+
+	if iterator.opts.Marker == nil || iterator.opts.Limit < 1 || iterator.count != iterator.opts.Limit {
+		iterator.done = true
+		return
+	}
+	marker := iterator.opts.Marker(iterator.last)
+	if marker == "" {
+		iterator.done = true
+		return
+	}
+	if marker == iterator.marker {
+		iterator.fail(fmt.Errorf("selvpcclient: %w: %s", errIteratorMarkerLoop, marker))
+		return
+	}
+	iterator.marker = marker
+}
+
+// fail stops the iteration with the provided error.
+func (iterator *ListIterator) fail(err error) {
+	iterator.err = err
+	iterator.closeBody()
+}
+
+// closeBody closes the response body of the current page.
+func (iterator *ListIterator) closeBody() error {
+	if iterator.result == nil || iterator.result.Body == nil {
+		return nil
+	}
+	body := iterator.result.Body
+	iterator.result.Body = nil
+
+	return body.Close()
+}
+
+// seekJSONArray reads the decoder tokens until the opening bracket of the
+// array with the provided key of the top-level object. It returns false if
+// the key is absent or its value is null.
+func seekJSONArray(decoder *json.Decoder, key string) (bool, error) {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return false, fmt.Errorf("selvpcclient: %w", errIteratorNotObject)
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return false, err
+		}
+		if name, _ := token.(string); name != key {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return false, err
+		}
+		if token == nil {
+			return false, nil
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return false, fmt.Errorf("selvpcclient: %w: %s", errIteratorNotArray, key)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,239 @@
+package selvpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in the debug log.
+const redactedValue = "[REDACTED]"
+
+// redactedHeaders contains headers whose values are not written to the debug
+// log.
+var redactedHeaders = map[string]struct{}{
+	"X-Token":       {},
+	"X-Auth-Token":  {},
+	"Authorization": {},
+}
+
+// redactedFields contains JSON fields whose values are not written to the
+// debug log, like users.UserOpts passwords.
+var redactedFields = map[string]struct{}{
+	"password": {},
+}
+
+// redactedObjectsIDs contains JSON objects whose "id" fields are not written to
+// the debug log, like tokens returned by tokens.Create.
+var redactedObjectsIDs = map[string]struct{}{
+	"token": {},
+}
+
+// redactedPathSegments contains URL path segments that are followed by a
+// secret ID, like the token ID of tokens.Delete.
+var redactedPathSegments = map[string]struct{}{
+	"tokens": {},
+}
+
+// Logger is used by the ServiceClient to report about its internal events
+// like retries. Standard *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// logf writes the message with the ServiceClient's Logger if it's set.
+func (client *ServiceClient) logf(format string, args ...interface{}) {
+	if client.Logger == nil {
+		return
+	}
+	client.Logger.Printf(format, args...)
+}
+
+// DebugMiddleware returns a middleware that writes every request and response
+// with headers and bodies to the provided logger. Authentication headers,
+// passwords and token IDs are redacted.
+// Response body is buffered so it can still be extracted after logging.
+func DebugMiddleware(logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			var requestBody []byte
+			if request.GetBody != nil {
+				if body, err := request.GetBody(); err == nil {
+					requestBody, _ = ioutil.ReadAll(body)
+					body.Close()
+				}
+			}
+			requestURL := redactURL(request.URL)
+			logger.Printf("selvpcclient: request %s %s\nHeaders:\n%sBody: %s",
+				request.Method, requestURL, formatHeaders(request.Header), redactBody(requestBody))
+
+			start := time.Now()
+			result, err := next(request)
+			latency := time.Since(start)
+			if err != nil {
+				logger.Printf("selvpcclient: request %s %s failed after %s: %v",
+					request.Method, requestURL, latency, err)
+				return result, err
+			}
+
+			responseBody, _ := result.RawBody()
+			logger.Printf("selvpcclient: response %s %s: %d in %s\nHeaders:\n%sBody: %s",
+				request.Method, requestURL, result.StatusCode, latency,
+				formatHeaders(result.Header), redactBody(responseBody))
+
+			return result, nil
+		}
+	}
+}
+
+// formatHeaders returns sorted headers with redacted secrets, one per line.
+func formatHeaders(headers http.Header) string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		value := strings.Join(headers[key], ", ")
+		if _, ok := redactedHeaders[http.CanonicalHeaderKey(key)]; ok {
+			value = redactedValue
+		}
+		fmt.Fprintf(&builder, "  %s: %s\n", key, value)
+	}
+
+	return builder.String()
+}
+
+// redactURL returns the URL with redacted path segments that follow the
+// redactedPathSegments.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	segments := strings.Split(u.EscapedPath(), "/")
+	redacted := false
+	for i := 1; i < len(segments); i++ {
+		if _, ok := redactedPathSegments[segments[i-1]]; ok && segments[i] != "" {
+			segments[i] = redactedValue
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	redactedURL := *u
+	redactedURL.RawPath = strings.Join(segments, "/")
+	redactedURL.Path, _ = url.PathUnescape(redactedURL.RawPath)
+
+	return redactedURL.String()
+}
+
+// redactBody returns the JSON body with redacted secrets. Only values of the
+// secret fields are replaced, so the rest of the body is written as is.
+// Non-JSON bodies are returned as is.
+func redactBody(body []byte) string {
+	if len(body) == 0 || !json.Valid(body) {
+		return string(body)
+	}
+
+	var spans []redactedSpan
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := collectRedactedSpans(decoder, body, false, &spans); err != nil {
+		return string(body)
+	}
+	if len(spans) == 0 {
+		return string(body)
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(body))
+	offset := int64(0)
+	for _, span := range spans {
+		builder.Write(body[offset:span.start])
+		builder.WriteString(`"` + redactedValue + `"`)
+		offset = span.end
+	}
+	builder.Write(body[offset:])
+
+	return builder.String()
+}
+
+// redactedSpan represents the position of the redacted JSON value in the body.
+type redactedSpan struct {
+	start int64
+	end   int64
+}
+
+// collectRedactedSpans walks through the next JSON value of the decoder and
+// collects positions of secret values. The "id" field is a secret if the
+// redactID is set.
+func collectRedactedSpans(decoder *json.Decoder, body []byte, redactID bool, spans *[]redactedSpan) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			_, secret := redactedFields[key]
+			if secret || (redactID && key == "id") {
+				start := valueStart(body, decoder.InputOffset())
+				var skipped json.RawMessage
+				if err := decoder.Decode(&skipped); err != nil {
+					return err
+				}
+				*spans = append(*spans, redactedSpan{start: start, end: decoder.InputOffset()})
+				continue
+			}
+			_, redactChildID := redactedObjectsIDs[key]
+			if err := collectRedactedSpans(decoder, body, redactChildID, spans); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for decoder.More() {
+			if err := collectRedactedSpans(decoder, body, false, spans); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Consume the closing delimiter.
+	_, err = decoder.Token()
+
+	return err
+}
+
+// valueStart returns the position of the object value that follows the key
+// ending at the provided offset.
+func valueStart(body []byte, offset int64) int64 {
+	for offset < int64(len(body)) {
+		switch body[offset] {
+		case ' ', '\t', '\n', '\r', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+
+	return offset
+}
@@ -0,0 +1,94 @@
+package selvpcclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// StatusClassError represents requests that failed without a response.
+const StatusClassError = "error"
+
+// RequestMetrics contains measurements of a single ServiceClient's request.
+type RequestMetrics struct {
+	// Operation describes the called resource and operation.
+	Operation Operation
+
+	// Method contains the HTTP method of the request.
+	Method string
+
+	// StatusCode contains the HTTP status code of the final response.
+	// It's zero if the request failed without a response.
+	StatusCode int
+
+	// StatusClass contains the status code class like "2xx" or "5xx".
+	// It's StatusClassError if the request failed without a response.
+	StatusClass string
+
+	// Duration contains the time spent on the request including retries.
+	Duration time.Duration
+
+	// Retries contains the number of retries.
+	Retries int
+
+	// Err contains the error of the request, either a transport error or an
+	// API error.
+	Err error
+}
+
+// MetricsRecorder records metrics of the ServiceClient's requests.
+// See the metrics/promselvpc package for the Prometheus adapter.
+type MetricsRecorder interface {
+	// ObserveRequest is called once for every request after it's finished.
+	ObserveRequest(metrics RequestMetrics)
+}
+
+// MetricsMiddleware returns a middleware that reports metrics of every
+// request to the provided recorder.
+func MetricsMiddleware(recorder MetricsRecorder, endpoint string) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			start := time.Now()
+			result, err := next(request)
+
+			metrics := RequestMetrics{
+				Operation:   OperationFromRequest(endpoint, request),
+				Method:      request.Method,
+				StatusClass: StatusClassError,
+				Duration:    time.Since(start),
+				Err:         err,
+			}
+			if result != nil {
+				metrics.StatusCode = result.StatusCode
+				metrics.StatusClass = statusClass(result.StatusCode)
+				if result.Attempts > 1 {
+					metrics.Retries = result.Attempts - 1
+				}
+				if err == nil {
+					metrics.Err = result.Err
+				}
+			}
+			recorder.ObserveRequest(metrics)
+
+			return result, err
+		}
+	}
+}
+
+// statusClass returns the class of the provided status code like "2xx".
+func statusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return StatusClassError
+	}
+
+	return string(rune('0'+statusCode/100)) + "xx"
+}
+
+// metricsMiddlewares returns the MetricsMiddleware if the ServiceClient's
+// Metrics recorder is set.
+func (client *ServiceClient) metricsMiddlewares() []Middleware {
+	if client.Metrics == nil {
+		return nil
+	}
+
+	return []Middleware{MetricsMiddleware(client.Metrics, client.Endpoint)}
+}
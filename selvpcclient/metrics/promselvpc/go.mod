@@ -0,0 +1,10 @@
+module github.com/selectel/go-selvpcclient/selvpcclient/metrics/promselvpc
+
+go 1.14
+
+require (
+	github.com/prometheus/client_golang v1.9.0
+	github.com/selectel/go-selvpcclient v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/selectel/go-selvpcclient => ../../..
@@ -0,0 +1,96 @@
+/*
+Package promselvpc provides the Prometheus adapter for the selvpcclient
+MetricsRecorder interface.
+
+It's a separate module, so the core library doesn't depend on Prometheus.
+
+Example of measuring all requests of the Resell client
+
+  collector := promselvpc.NewCollector("myservice")
+  prometheus.MustRegister(collector)
+
+  resellClient := resell.NewV2ResellClient(token)
+  resellClient.Metrics = collector
+*/
+package promselvpc
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+const subsystem = "selvpcclient"
+
+// labels contains labels of all request metrics.
+var labels = []string{"resource", "operation", "method"}
+
+// Collector implements both prometheus.Collector and the
+// selvpcclient.MetricsRecorder interfaces.
+type Collector struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewCollector returns a reference to the Collector with metrics in the
+// provided namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Total number of Selectel VPC API requests.",
+		}, append(labels, "status_class")),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_errors_total",
+			Help:      "Total number of failed Selectel VPC API requests by status class.",
+		}, append(labels, "status_class")),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_retries_total",
+			Help:      "Total number of Selectel VPC API request retries.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of Selectel VPC API requests including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// ObserveRequest records metrics of a single request.
+func (c *Collector) ObserveRequest(metrics selvpcclient.RequestMetrics) {
+	values := []string{metrics.Operation.Resource, metrics.Operation.Name, metrics.Method}
+
+	c.requests.WithLabelValues(append(values, metrics.StatusClass)...).Inc()
+	if metrics.Err != nil {
+		c.errors.WithLabelValues(append(values, metrics.StatusClass)...).Inc()
+	}
+	if metrics.Retries > 0 {
+		c.retries.WithLabelValues(values...).Add(float64(metrics.Retries))
+	}
+	c.duration.WithLabelValues(values...).Observe(metrics.Duration.Seconds())
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.retries.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.retries.Collect(ch)
+	c.duration.Collect(ch)
+}
@@ -0,0 +1,56 @@
+package testing
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/metrics/promselvpc"
+)
+
+func TestCollector(t *testing.T) {
+	collector := promselvpc.NewCollector("test")
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("unable to register the collector: %v", err)
+	}
+
+	operation := selvpcclient.Operation{Resource: "floatingips", Name: selvpcclient.OperationCreate}
+	collector.ObserveRequest(selvpcclient.RequestMetrics{
+		Operation:   operation,
+		Method:      http.MethodPost,
+		StatusCode:  http.StatusCreated,
+		StatusClass: "2xx",
+		Duration:    100 * time.Millisecond,
+		Retries:     2,
+	})
+	collector.ObserveRequest(selvpcclient.RequestMetrics{
+		Operation:   operation,
+		Method:      http.MethodPost,
+		StatusCode:  http.StatusConflict,
+		StatusClass: "4xx",
+		Duration:    50 * time.Millisecond,
+		Err:         &selvpcclient.APIError{StatusCode: http.StatusConflict},
+	})
+
+	count, err := testutil.GatherAndCount(registry)
+	if err != nil {
+		t.Fatalf("unable to gather metrics: %v", err)
+	}
+	// Two requests series, one errors series, one retries series and one
+	// histogram.
+	if count != 5 {
+		t.Fatalf("expected 5 metric series, but got %d", count)
+	}
+
+	retries, err := testutil.GatherAndCount(registry, "test_selvpcclient_request_retries_total")
+	if err != nil {
+		t.Fatalf("unable to gather metrics: %v", err)
+	}
+	if retries != 1 {
+		t.Fatalf("expected retries series, but got %d", retries)
+	}
+}
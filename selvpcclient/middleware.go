@@ -0,0 +1,75 @@
+package selvpcclient
+
+import "net/http"
+
+// Handler sends a prepared HTTP request and returns its result.
+type Handler func(request *http.Request) (*ResponseResult, error)
+
+// Middleware wraps a Handler to add some behaviour around the request/response
+// cycle. It can modify the request before calling the next handler, inspect
+// the returned ResponseResult or call the next handler several times.
+type Middleware func(next Handler) Handler
+
+// HeaderMiddleware returns a middleware that sets the provided header for
+// every request.
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			request.Header.Set(key, value)
+			return next(request)
+		}
+	}
+}
+
+// UserAgentMiddleware returns a middleware that sets the User-Agent header.
+func UserAgentMiddleware(userAgent string) Middleware {
+	return HeaderMiddleware("User-Agent", userAgent)
+}
+
+// TokenMiddleware returns a middleware that sets the X-token authentication
+// header.
+func TokenMiddleware(tokenID string) Middleware {
+	return HeaderMiddleware("X-token", tokenID)
+}
+
+// defaultMiddlewares returns middlewares that are applied to every request
+// before the ServiceClient's Middlewares.
+func (client *ServiceClient) defaultMiddlewares() []Middleware {
+	tokenMiddleware := TokenMiddleware(client.TokenID)
+	if client.TokenSource != nil {
+		tokenMiddleware = TokenSourceMiddleware(client.TokenSource)
+	}
+
+	return []Middleware{
+		UserAgentMiddleware(client.UserAgent),
+		tokenMiddleware,
+		IdempotencyKeyMiddleware(),
+	}
+}
+
+// debugMiddlewares returns the DebugMiddleware if the debug mode is enabled.
+// It's applied after all other middlewares to log the final request.
+func (client *ServiceClient) debugMiddlewares() []Middleware {
+	if !client.Debug || client.Logger == nil {
+		return nil
+	}
+
+	return []Middleware{DebugMiddleware(client.Logger)}
+}
+
+// chainMiddlewares wraps the handler with the provided middlewares so the
+// first middleware is called first.
+func chainMiddlewares(handler Handler, middlewares ...[]Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		for j := len(middlewares[i]) - 1; j >= 0; j-- {
+			handler = middlewares[i][j](handler)
+		}
+	}
+
+	return handler
+}
+
+// Use appends the provided middlewares to the ServiceClient's Middlewares.
+func (client *ServiceClient) Use(middlewares ...Middleware) {
+	client.Middlewares = append(client.Middlewares, middlewares...)
+}
@@ -0,0 +1,243 @@
+package selvpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	errPoolEmptyName   = errors.New("account name is empty")
+	errPoolNilClient   = errors.New("client is nil")
+	errPoolDuplicate   = errors.New("account already exists")
+	errPoolUnknownName = errors.New("account is not found")
+	errPoolNilFunction = errors.New("pool function is nil")
+	errPoolNoAccounts  = errors.New("pool has no accounts")
+)
+
+// ErrPoolPanic is returned in the PoolResult's Err if the PoolFunc panics
+// for the account.
+var ErrPoolPanic = errors.New("selvpcclient: pool function panicked")
+
+// defaultPoolParallelism represents the default number of accounts that are
+// processed at the same time.
+const defaultPoolParallelism = 4
+
+// PoolFunc is called by the ClientPool for every account.
+type PoolFunc func(ctx context.Context, account string, client *ServiceClient) (interface{}, error)
+
+// PoolResult contains the result of the PoolFunc for a single account.
+type PoolResult struct {
+	// Account contains the name of the account.
+	Account string
+
+	// Value contains the value returned by the PoolFunc.
+	Value interface{}
+
+	// Err contains the error returned by the PoolFunc.
+	Err error
+}
+
+// PoolResults contains results of the ClientPool's Run sorted by account
+// names.
+type PoolResults []PoolResult
+
+// Get returns the result of the provided account.
+func (results PoolResults) Get(account string) (PoolResult, bool) {
+	for _, result := range results {
+		if result.Account == account {
+			return result, true
+		}
+	}
+
+	return PoolResult{}, false
+}
+
+// Err returns the PoolError with errors of all failed accounts.
+// It returns nil if all accounts succeeded.
+func (results PoolResults) Err() error {
+	poolErr := &PoolError{Errors: make(map[string]error)}
+	for _, result := range results {
+		if result.Err != nil {
+			poolErr.Errors[result.Account] = result.Err
+		}
+	}
+	if len(poolErr.Errors) == 0 {
+		return nil
+	}
+
+	return poolErr
+}
+
+// PoolError contains errors of the failed accounts.
+type PoolError struct {
+	// Errors contains errors by account names.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *PoolError) Error() string {
+	accounts := make([]string, 0, len(e.Errors))
+	for account := range e.Errors {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	messages := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		messages = append(messages, fmt.Sprintf("%s: %v", account, e.Errors[account]))
+	}
+
+	return fmt.Sprintf("selvpcclient: %d of the accounts failed: %s", len(accounts), strings.Join(messages, "; "))
+}
+
+// ClientPool holds ServiceClients of several accounts by their names and runs
+// the same function across them concurrently.
+// It's safe for concurrent use.
+type ClientPool struct {
+	parallelism int
+
+	mu      sync.RWMutex
+	clients map[string]*ServiceClient
+}
+
+// NewClientPool returns a reference to the empty ClientPool that processes
+// up to the provided number of accounts at the same time.
+// Default parallelism is used if it's less than 1.
+func NewClientPool(parallelism int) *ClientPool {
+	if parallelism < 1 {
+		parallelism = defaultPoolParallelism
+	}
+
+	return &ClientPool{
+		parallelism: parallelism,
+		clients:     make(map[string]*ServiceClient),
+	}
+}
+
+// Add adds the client of the named account to the pool.
+func (pool *ClientPool) Add(account string, client *ServiceClient) error {
+	if account == "" {
+		return fmt.Errorf("selvpcclient: %w", errPoolEmptyName)
+	}
+	if client == nil {
+		return fmt.Errorf("selvpcclient: %w: %s", errPoolNilClient, account)
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if _, ok := pool.clients[account]; ok {
+		return fmt.Errorf("selvpcclient: %w: %s", errPoolDuplicate, account)
+	}
+	pool.clients[account] = client
+
+	return nil
+}
+
+// Remove removes the named account from the pool.
+func (pool *ClientPool) Remove(account string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	delete(pool.clients, account)
+}
+
+// Client returns the client of the named account.
+func (pool *ClientPool) Client(account string) (*ServiceClient, error) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	client, ok := pool.clients[account]
+	if !ok {
+		return nil, fmt.Errorf("selvpcclient: %w: %s", errPoolUnknownName, account)
+	}
+
+	return client, nil
+}
+
+// Accounts returns sorted names of all accounts in the pool.
+func (pool *ClientPool) Accounts() []string {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	accounts := make([]string, 0, len(pool.clients))
+	for account := range pool.clients {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	return accounts
+}
+
+// Run calls the provided function for every account of the pool
+// concurrently and waits for all of them. Accounts that weren't processed
+// because the context is done get the context error. A panic of the function
+// is recovered and returned as the ErrPoolPanic of its account.
+// Results are returned for every account, check their errors or use the
+// PoolResults's Err method.
+//
+//	results, err := pool.Run(ctx, func(ctx context.Context, _ string, client *selvpcclient.ServiceClient) (interface{}, error) {
+//		freeQuotas, _, err := quotas.GetFree(ctx, client)
+//		return freeQuotas, err
+//	})
+func (pool *ClientPool) Run(ctx context.Context, fn PoolFunc) (PoolResults, error) {
+	if fn == nil {
+		return nil, fmt.Errorf("selvpcclient: %w", errPoolNilFunction)
+	}
+
+	pool.mu.RLock()
+	accounts := make([]string, 0, len(pool.clients))
+	clients := make(map[string]*ServiceClient, len(pool.clients))
+	for account, client := range pool.clients {
+		accounts = append(accounts, account)
+		clients[account] = client
+	}
+	pool.mu.RUnlock()
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("selvpcclient: %w", errPoolNoAccounts)
+	}
+	sort.Strings(accounts)
+
+	results := make(PoolResults, len(accounts))
+	semaphore := make(chan struct{}, pool.parallelism)
+	var wg sync.WaitGroup
+
+	for i, account := range accounts {
+		results[i].Account = account
+
+		select {
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		case semaphore <- struct{}{}:
+		}
+		// Context is checked again because select chooses randomly if both
+		// cases are ready.
+		if err := ctx.Err(); err != nil {
+			<-semaphore
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *PoolResult, client *ServiceClient) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			defer func() {
+				if r := recover(); r != nil {
+					result.Value = nil
+					result.Err = fmt.Errorf("%w for the %s account: %v", ErrPoolPanic, result.Account, r)
+				}
+			}()
+
+			result.Value, result.Err = fn(ctx, result.Account, client)
+		}(&results[i], clients[account])
+	}
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,224 @@
+package selvpcclient
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// queryTagName contains the name of the struct tag that describes query
+	// parameters.
+	queryTagName = "param"
+
+	// queryOptionComma joins slice values with commas into a single parameter
+	// instead of repeating the parameter for every value.
+	queryOptionComma = "comma"
+
+	// queryOptionFormat sets the layout of time.Time values. The "unix" layout
+	// formats times as Unix seconds.
+	queryOptionFormat = "format="
+
+	// queryFormatUnix formats time.Time values as Unix seconds.
+	queryFormatUnix = "unix"
+)
+
+var errUnsupportedQueryKind = errors.New("unsupported kind of the query parameter")
+
+// timeType is used to detect time.Time fields.
+var timeType = reflect.TypeOf(time.Time{})
+
+// QueryEncoder can be implemented by custom types of the options fields to
+// control how they are converted to the query parameter.
+type QueryEncoder interface {
+	// EncodeQueryParameter returns the value of the query parameter.
+	EncodeQueryParameter() (string, error)
+}
+
+// BuildQueryParameters converts provided options struct to the string of URL parameters.
+// Fields with the "param" tag are converted, zero values are skipped.
+// Strings, booleans, signed and unsigned integers, floats, time.Time values,
+// slices and arrays of them and types that implement the QueryEncoder are
+// supported, pointers are dereferenced. Fields of embedded structs without the
+// tag are converted as the fields of the outer struct.
+// The tag can contain options after the parameter name:
+//
+//	IDs   []string  `param:"id"`                        // id=1&id=2
+//	Tags  []string  `param:"tags,comma"`                // tags=a%2Cb
+//	Since time.Time `param:"since"`                     // RFC3339
+//	Day   time.Time `param:"day,format=2006-01-02"`     // custom layout without commas
+//	Until time.Time `param:"until,format=unix"`         // Unix seconds
+//
+// An error is returned for fields of other kinds.
+func BuildQueryParameters(opts interface{}) (string, error) {
+	optsValue := reflect.ValueOf(opts)
+	if optsValue.Kind() != reflect.Struct {
+		return "", errOptsIsNotStruct
+	}
+
+	params := url.Values{}
+	if err := addStructQueryParameters(params, optsValue); err != nil {
+		return "", err
+	}
+
+	return params.Encode(), nil
+}
+
+// addStructQueryParameters adds parameters of all tagged fields of the struct.
+func addStructQueryParameters(params url.Values, structValue reflect.Value) error {
+	structType := structValue.Type()
+
+	for i := 0; i < structValue.NumField(); i++ {
+		fieldValue := structValue.Field(i)
+		fieldType := structType.Field(i)
+
+		queryTag := fieldType.Tag.Get(queryTagName)
+		if queryTag == "" {
+			if !fieldType.Anonymous {
+				continue
+			}
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct {
+				if err := addStructQueryParameters(params, fieldValue); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if fieldValue.IsZero() {
+			continue
+		}
+
+		tags := strings.Split(queryTag, ",")
+		name, options := tags[0], tags[1:]
+		values, err := queryValues(fieldValue, options)
+		if err != nil {
+			return fmt.Errorf("unable to build the %s query parameter from the %s field: %w", name, fieldType.Name, err)
+		}
+		if hasQueryOption(options, queryOptionComma) && len(values) > 0 {
+			values = []string{strings.Join(values, ",")}
+		}
+		for _, value := range values {
+			params.Add(name, value)
+		}
+	}
+
+	return nil
+}
+
+// queryValues converts the field value into the query parameter values.
+func queryValues(value reflect.Value, options []string) ([]string, error) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	if s, ok, err := encodeQueryValue(value); ok {
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elemValues, err := queryValues(value.Index(i), options)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elemValues...)
+		}
+		return values, nil
+	case reflect.Struct:
+		if value.Type() != timeType {
+			break
+		}
+		return []string{formatQueryTime(value.Interface().(time.Time), options)}, nil
+	}
+
+	s, err := formatQueryScalar(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{s}, nil
+}
+
+// encodeQueryValue uses the QueryEncoder implementation of the value if it
+// exists. The second returned value shows if the value is the QueryEncoder.
+func encodeQueryValue(value reflect.Value) (string, bool, error) {
+	if value.CanInterface() {
+		if encoder, ok := value.Interface().(QueryEncoder); ok {
+			s, err := encoder.EncodeQueryParameter()
+			return s, true, err
+		}
+	}
+	if value.CanAddr() && value.Addr().CanInterface() {
+		if encoder, ok := value.Addr().Interface().(QueryEncoder); ok {
+			s, err := encoder.EncodeQueryParameter()
+			return s, true, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// formatQueryScalar converts the value of a basic kind into the string.
+func formatQueryScalar(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", errUnsupportedQueryKind, value.Type())
+}
+
+// formatQueryTime formats the time with the layout from the tag options.
+// RFC3339 layout is used by default.
+func formatQueryTime(t time.Time, options []string) string {
+	layout := time.RFC3339
+	for _, option := range options {
+		if strings.HasPrefix(option, queryOptionFormat) {
+			layout = strings.TrimPrefix(option, queryOptionFormat)
+		}
+	}
+	if layout == queryFormatUnix {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	return t.Format(layout)
+}
+
+// hasQueryOption checks if the tag options contain the provided option.
+func hasQueryOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
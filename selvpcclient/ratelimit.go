@@ -0,0 +1,139 @@
+package selvpcclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errRateLimitExceedsDeadline = errors.New("rate limiter wait exceeds the context deadline")
+
+// RateLimiter limits the rate of requests sent by the ServiceClient with the
+// token bucket algorithm. Every request takes a single token from the bucket
+// of its HTTP method or from the default bucket if the method has no own limit.
+// It's safe for concurrent use and can be shared between several clients.
+// The zero value has no limits until SetMethodLimit is called.
+type RateLimiter struct {
+	// OnWait is called every time a request has to wait for a free token.
+	OnWait func(request *http.Request, wait time.Duration)
+
+	mu             sync.Mutex
+	defaultBucket  *tokenBucket
+	methodsBuckets map[string]*tokenBucket
+}
+
+// NewRateLimiter returns a reference to the RateLimiter that allows the
+// provided number of requests per second with the provided burst.
+// Zero or negative rps value means no default limit.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		defaultBucket:  newTokenBucket(rps, burst),
+		methodsBuckets: make(map[string]*tokenBucket),
+	}
+}
+
+// SetMethodLimit sets a separate limit for requests with the provided HTTP
+// method. Zero or negative rps value means no limit for the method.
+func (limiter *RateLimiter) SetMethodLimit(method string, rps float64, burst int) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limiter.methodsBuckets == nil {
+		limiter.methodsBuckets = make(map[string]*tokenBucket)
+	}
+	limiter.methodsBuckets[method] = newTokenBucket(rps, burst)
+}
+
+// Wait blocks until the request can be sent or the request's context is done.
+// It returns the time spent waiting.
+func (limiter *RateLimiter) Wait(request *http.Request) (time.Duration, error) {
+	ctx := request.Context()
+
+	limiter.mu.Lock()
+	bucket, ok := limiter.methodsBuckets[request.Method]
+	if !ok {
+		bucket = limiter.defaultBucket
+	}
+	wait := bucket.reserve(time.Now())
+	limiter.mu.Unlock()
+
+	if wait <= 0 {
+		return 0, nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		limiter.cancel(bucket)
+		return 0, fmt.Errorf("selvpcclient: %w: need to wait %s", errRateLimitExceedsDeadline, wait)
+	}
+	if limiter.OnWait != nil {
+		limiter.OnWait(request, wait)
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		limiter.cancel(bucket)
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// cancel returns an unused token to the bucket.
+func (limiter *RateLimiter) cancel(bucket *tokenBucket) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	bucket.tokens++
+}
+
+// tokenBucket represents a single token bucket.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a reference to the full tokenBucket.
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller needs
+// to wait before it can be used. The nil bucket has no limit.
+func (bucket *tokenBucket) reserve(now time.Time) time.Duration {
+	if bucket == nil || bucket.rate <= 0 {
+		return 0
+	}
+	if !bucket.last.IsZero() {
+		bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+	}
+	bucket.last = now
+
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+}
+
+// waitRateLimit waits for the ServiceClient's RateLimiter if it's set.
+func (client *ServiceClient) waitRateLimit(request *http.Request) error {
+	if client.RateLimiter == nil {
+		return nil
+	}
+	_, err := client.RateLimiter.Wait(request)
+
+	return err
+}
@@ -0,0 +1,118 @@
+package selvpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	errReconcileNilList   = errors.New("reconciliation list function is nil")
+	errReconcileNilCreate = errors.New("reconciliation create function is nil")
+)
+
+// ReconcileItem represents a listed resource for the CreateWithReconciliation.
+type ReconcileItem struct {
+	// ID contains the resource ID.
+	ID string
+
+	// Key contains the parameters of the resource that are set by the create
+	// options, like its region. Keys of the resources must match keys of the
+	// ReconcileOpts's Requested.
+	Key string
+
+	// Value contains the resource itself.
+	Value interface{}
+}
+
+// ReconcileOpts represents parameters of the CreateWithReconciliation.
+type ReconcileOpts struct {
+	// List returns resources of the project that can be created by the Create.
+	List func(ctx context.Context) ([]ReconcileItem, error)
+
+	// Create requests a creation of the resources.
+	Create func(ctx context.Context) (*ResponseResult, error)
+
+	// Requested contains the requested quantity of resources by their keys.
+	Requested map[string]int
+
+	// Existing contains resources that existed before the creation, for
+	// example from a previous List call. The List is called before the
+	// creation to get them if it's nil.
+	Existing []ReconcileItem
+}
+
+// CreateWithReconciliation calls the Create and protects from a double
+// allocation if the result of the request is unknown. The request is sent
+// with the idempotency key from the context or with a new one.
+//
+// If it fails with an ambiguous error, resources are listed again. New
+// resources are returned instead of the error only if their quantity matches
+// the requested one for every key. The Create is called again with the same
+// idempotency key if no new resources are found, and the original error is
+// returned if the quantity doesn't match.
+//
+// Resources that are created with the same parameters by other clients
+// between the listings can't be distinguished from the requested ones, so
+// don't use it for projects that are modified concurrently.
+//
+// Values of the reconciled resources are returned, they're nil if the result
+// of the Create should be used.
+func CreateWithReconciliation(ctx context.Context, opts ReconcileOpts) ([]interface{}, *ResponseResult, error) {
+	if opts.List == nil {
+		return nil, nil, fmt.Errorf("selvpcclient: %w", errReconcileNilList)
+	}
+	if opts.Create == nil {
+		return nil, nil, fmt.Errorf("selvpcclient: %w", errReconcileNilCreate)
+	}
+
+	existingItems := opts.Existing
+	if existingItems == nil {
+		var err error
+		existingItems, err = opts.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	existing := make(map[string]struct{}, len(existingItems))
+	for _, item := range existingItems {
+		existing[item.ID] = struct{}{}
+	}
+
+	createCtx := EnsureIdempotencyKey(ctx)
+	responseResult, err := opts.Create(createCtx)
+	if !IsAmbiguousError(err) {
+		return nil, responseResult, err
+	}
+
+	// Check if the resources were created despite the error.
+	currentItems, listErr := opts.List(ctx)
+	if listErr != nil {
+		return nil, responseResult, err
+	}
+	var created []interface{}
+	found := make(map[string]int, len(opts.Requested))
+	for _, item := range currentItems {
+		if _, ok := existing[item.ID]; ok {
+			continue
+		}
+		if _, ok := opts.Requested[item.Key]; !ok {
+			continue
+		}
+		found[item.Key]++
+		created = append(created, item.Value)
+	}
+	if len(created) == 0 {
+		responseResult, err = opts.Create(createCtx)
+		return nil, responseResult, err
+	}
+	for key, quantity := range opts.Requested {
+		if found[key] != quantity {
+			return nil, responseResult, fmt.Errorf(
+				"selvpcclient: found %d new resources for %q instead of %d, unable to reconcile: %w",
+				found[key], key, quantity, err)
+		}
+	}
+
+	return created, responseResult, nil
+}
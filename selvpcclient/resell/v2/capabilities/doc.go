@@ -9,5 +9,22 @@ Example of getting domain capabilities
     log.Fatal(err)
 	}
 	fmt.Println(domainCapabilities)
+
+Example of checking subnet options against domain capabilities
+
+  validator := capabilities.NewValidator(domainCapabilities)
+  createOpts := subnets.SubnetOpts{
+    Subnets: []subnets.SubnetOpt{
+      {
+        Region:       "ru-3",
+        Quantity:     1,
+        Type:         selvpcclient.IPv4,
+        PrefixLength: 29,
+      },
+    },
+  }
+  if err := validator.ValidateSubnetOpts(createOpts); err != nil {
+    log.Fatal(err)
+  }
 */
 package capabilities
@@ -3,7 +3,6 @@ package capabilities
 import (
 	"context"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -12,7 +11,10 @@ const resourceURL = "capabilities"
 
 // Get returns the domain capabilities.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient) (*Capabilities, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
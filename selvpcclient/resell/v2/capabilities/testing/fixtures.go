@@ -1,5 +1,7 @@
 package testing
 
+import "github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/capabilities"
+
 // TestGetCapabilitiesRaw represents a raw response from the Get request.
 const TestGetCapabilitiesRaw = `
 {
@@ -225,3 +227,53 @@ const TestGetCapabilitiesInvalidRaw = `
     }
 }
 `
+
+// TestValidatorCapabilities represents capabilities that are used to test the
+// Validator.
+var TestValidatorCapabilities = &capabilities.Capabilities{
+	Licenses: []capabilities.License{
+		{
+			Availability: []string{"ru-1", "ru-2"},
+			Type:         "license_windows_2016_standard",
+		},
+	},
+	Regions: []capabilities.Region{
+		{
+			Name: "ru-1",
+			Zones: []capabilities.Zone{
+				{Name: "ru-1a", Enabled: true},
+				{Name: "ru-1b", Enabled: false},
+			},
+		},
+		{
+			Name: "ru-2",
+			Zones: []capabilities.Zone{
+				{Name: "ru-2a", Enabled: true},
+			},
+		},
+		{
+			Name: "ru-3",
+			Zones: []capabilities.Zone{
+				{Name: "ru-3a", Enabled: true},
+			},
+		},
+	},
+	Resources: []capabilities.Resource{
+		{Name: "compute_cores", QuotaScope: "zone", Quotable: true},
+		{Name: "image_gigabytes", QuotaScope: "region", Quotable: true},
+		{Name: "network_floatingips", Quotable: false},
+		{Name: "storage_buckets", Quotable: true},
+	},
+	Subnets: []capabilities.Subnet{
+		{
+			Availability: []string{"ru-1", "ru-2"},
+			Type:         "ipv4",
+			PrefixLength: "29",
+		},
+		{
+			Availability: []string{"ru-1"},
+			Type:         "ipv4",
+			PrefixLength: "28",
+		},
+	},
+}
@@ -0,0 +1,276 @@
+package testing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/capabilities"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/floatingips"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/licenses"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/subnets"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/vrrpsubnets"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func checkFieldErrors(t *testing.T, err error, expected []selvpcclient.FieldError) {
+	t.Helper()
+
+	if len(expected) == 0 {
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		return
+	}
+
+	var validationErr *selvpcclient.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, but got %v", err)
+	}
+	if !reflect.DeepEqual(validationErr.Errors, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, validationErr.Errors)
+	}
+}
+
+func TestValidatorFloatingIPOpts(t *testing.T) {
+	validator := capabilities.NewValidator(TestValidatorCapabilities)
+
+	err := validator.ValidateFloatingIPOpts(floatingips.FloatingIPOpts{
+		FloatingIPs: []floatingips.FloatingIPOpt{
+			{Region: "ru-1", Quantity: 1},
+			{Region: "ru-9", Quantity: 1},
+		},
+	})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{Field: "floatingips[1].region", Message: "ru-9 region doesn't exist, available: ru-1, ru-2, ru-3"},
+	})
+}
+
+func TestValidatorSubnetOpts(t *testing.T) {
+	validator := capabilities.NewValidator(TestValidatorCapabilities)
+
+	testCases := []struct {
+		name     string
+		opt      subnets.SubnetOpt
+		expected []selvpcclient.FieldError
+	}{
+		{
+			name: "available",
+			opt:  subnets.SubnetOpt{Region: "ru-1", Type: selvpcclient.IPv4, PrefixLength: 28},
+		},
+		{
+			name: "unknown region",
+			opt:  subnets.SubnetOpt{Region: "ru-9", Type: selvpcclient.IPv4, PrefixLength: 29},
+			expected: []selvpcclient.FieldError{
+				{Field: "region", Message: "ru-9 region doesn't exist, available: ru-1, ru-2, ru-3"},
+			},
+		},
+		{
+			name: "unavailable prefix",
+			opt:  subnets.SubnetOpt{Region: "ru-2", Type: selvpcclient.IPv4, PrefixLength: 28},
+			expected: []selvpcclient.FieldError{
+				{Field: "prefix_length", Message: "ipv4/28 subnets are not available in the ru-2 region, available: ipv4/29"},
+			},
+		},
+		{
+			name: "no subnets in region",
+			opt:  subnets.SubnetOpt{Region: "ru-3", Type: selvpcclient.IPv4, PrefixLength: 29},
+			expected: []selvpcclient.FieldError{
+				{Field: "prefix_length", Message: "ipv4/29 subnets are not available in the ru-3 region, nothing is available"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checkFieldErrors(t, validator.ValidateSubnetOpt(testCase.opt), testCase.expected)
+		})
+	}
+
+	err := validator.ValidateSubnetOpts(subnets.SubnetOpts{
+		Subnets: []subnets.SubnetOpt{testCases[0].opt, testCases[2].opt},
+	})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{Field: "subnets[1].prefix_length", Message: "ipv4/28 subnets are not available in the ru-2 region, available: ipv4/29"},
+	})
+}
+
+func TestValidatorLicenseOpts(t *testing.T) {
+	validator := capabilities.NewValidator(TestValidatorCapabilities)
+
+	testCases := []struct {
+		name     string
+		opt      licenses.LicenseOpt
+		expected []selvpcclient.FieldError
+	}{
+		{
+			name: "available",
+			opt:  licenses.LicenseOpt{Region: "ru-2", Type: "license_windows_2016_standard"},
+		},
+		{
+			name: "unavailable in region",
+			opt:  licenses.LicenseOpt{Region: "ru-3", Type: "license_windows_2016_standard"},
+			expected: []selvpcclient.FieldError{
+				{
+					Field:   "type",
+					Message: "license_windows_2016_standard license is not available in the ru-3 region, it's available in: ru-1, ru-2",
+				},
+			},
+		},
+		{
+			name: "unknown type and region",
+			opt:  licenses.LicenseOpt{Region: "ru-9", Type: "license_windows_2008"},
+			expected: []selvpcclient.FieldError{
+				{Field: "region", Message: "ru-9 region doesn't exist, available: ru-1, ru-2, ru-3"},
+				{Field: "type", Message: "license_windows_2008 license type is unknown, available: license_windows_2016_standard"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checkFieldErrors(t, validator.ValidateLicenseOpt(testCase.opt), testCase.expected)
+		})
+	}
+
+	err := validator.ValidateLicenseOpts(licenses.LicenseOpts{
+		Licenses: []licenses.LicenseOpt{testCases[0].opt, testCases[1].opt},
+	})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{
+			Field:   "licenses[1].type",
+			Message: "license_windows_2016_standard license is not available in the ru-3 region, it's available in: ru-1, ru-2",
+		},
+	})
+}
+
+func TestValidatorVRRPSubnetOpts(t *testing.T) {
+	validator := capabilities.NewValidator(TestValidatorCapabilities)
+
+	err := validator.ValidateVRRPSubnetOpts(vrrpsubnets.VRRPSubnetOpts{
+		VRRPSubnets: []vrrpsubnets.VRRPSubnetOpt{
+			{Regions: vrrpsubnets.VRRPRegionOpt{Master: "ru-1", Slave: "ru-2"}},
+			{Regions: vrrpsubnets.VRRPRegionOpt{Master: "ru-9", Slave: ""}},
+		},
+	})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{Field: "vrrp_subnets[1].regions.master", Message: "ru-9 region doesn't exist, available: ru-1, ru-2, ru-3"},
+		{Field: "vrrp_subnets[1].regions.slave", Message: "is required, available: ru-1, ru-2, ru-3"},
+	})
+}
+
+func TestValidatorResourceQuotaOpts(t *testing.T) {
+	validator := capabilities.NewValidator(TestValidatorCapabilities)
+
+	testCases := []struct {
+		name     string
+		resource string
+		opts     quotas.ResourceQuotaOpts
+		expected []selvpcclient.FieldError
+	}{
+		{
+			name:     "zone scope",
+			resource: "compute_cores",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-1"), Zone: stringPtr("ru-1a")},
+		},
+		{
+			name:     "zone scope without zone",
+			resource: "compute_cores",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-1")},
+			expected: []selvpcclient.FieldError{
+				{Field: "zone", Message: "is required, available: ru-1a"},
+			},
+		},
+		{
+			name:     "zone scope with disabled zone",
+			resource: "compute_cores",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-1"), Zone: stringPtr("ru-1b")},
+			expected: []selvpcclient.FieldError{
+				{Field: "zone", Message: "ru-1b zone is disabled"},
+			},
+		},
+		{
+			name:     "zone scope with zone of another region",
+			resource: "compute_cores",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-2"), Zone: stringPtr("ru-1a")},
+			expected: []selvpcclient.FieldError{
+				{Field: "zone", Message: "ru-1a zone doesn't exist in the ru-2 region, available: ru-2a"},
+			},
+		},
+		{
+			name:     "region scope",
+			resource: "image_gigabytes",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-3")},
+		},
+		{
+			name:     "region scope with zone",
+			resource: "image_gigabytes",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-3"), Zone: stringPtr("ru-3a")},
+			expected: []selvpcclient.FieldError{
+				{Field: "zone", Message: "must be empty because image_gigabytes quotas are set per region"},
+			},
+		},
+		{
+			name:     "global scope",
+			resource: "storage_buckets",
+		},
+		{
+			name:     "global scope with region",
+			resource: "storage_buckets",
+			opts:     quotas.ResourceQuotaOpts{Region: stringPtr("ru-1")},
+			expected: []selvpcclient.FieldError{
+				{Field: "region", Message: "must be empty because storage_buckets quotas are global"},
+			},
+		},
+		{
+			name:     "not quotable",
+			resource: "network_floatingips",
+			expected: []selvpcclient.FieldError{
+				{Field: "name", Message: "network_floatingips resource is not quotable"},
+			},
+		},
+		{
+			name:     "unknown resource",
+			resource: "compute_gpus",
+			expected: []selvpcclient.FieldError{
+				{
+					Field:   "name",
+					Message: "compute_gpus resource is unknown, available: compute_cores, image_gigabytes, storage_buckets",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validator.ValidateResourceQuotaOpts(testCase.resource, testCase.opts)
+			checkFieldErrors(t, err, testCase.expected)
+		})
+	}
+
+	err := validator.ValidateUpdateProjectQuotasOpts(quotas.UpdateProjectQuotasOpts{
+		QuotasOpts: []quotas.QuotaOpts{
+			{
+				Name:               "compute_cores",
+				ResourceQuotasOpts: []quotas.ResourceQuotaOpts{testCases[0].opts, testCases[2].opts},
+			},
+		},
+	})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{Field: "quotas[0].values[1].zone", Message: "ru-1b zone is disabled"},
+	})
+}
+
+func TestValidatorNilCapabilities(t *testing.T) {
+	validator := capabilities.NewValidator(nil)
+
+	err := validator.ValidateFloatingIPOpt(floatingips.FloatingIPOpt{Region: "ru-1"})
+	checkFieldErrors(t, err, []selvpcclient.FieldError{
+		{Field: "region", Message: "ru-1 region doesn't exist, nothing is available"},
+	})
+}
@@ -0,0 +1,313 @@
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/floatingips"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/licenses"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/subnets"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/vrrpsubnets"
+)
+
+const (
+	// QuotaScopeRegion represents resources whose quotas are set per region.
+	QuotaScopeRegion = "region"
+
+	// QuotaScopeZone represents resources whose quotas are set per zone.
+	QuotaScopeZone = "zone"
+)
+
+// Validator checks create options against the domain capabilities before
+// the request is sent. Every method returns the selvpcclient.ValidationError
+// that lists all unavailable values together with the available ones.
+type Validator struct {
+	capabilities *Capabilities
+}
+
+// NewValidator returns a reference to the Validator of the provided
+// capabilities.
+func NewValidator(capabilities *Capabilities) *Validator {
+	if capabilities == nil {
+		capabilities = &Capabilities{}
+	}
+
+	return &Validator{capabilities: capabilities}
+}
+
+// ValidateFloatingIPOpts checks that all floating ips can be created in their
+// regions.
+func (v *Validator) ValidateFloatingIPOpts(opts floatingips.FloatingIPOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, opt := range opts.FloatingIPs {
+		validationErr.Merge(fmt.Sprintf("floatingips[%d]", i), v.ValidateFloatingIPOpt(opt))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateFloatingIPOpt checks that the region of the floating ip exists.
+func (v *Validator) ValidateFloatingIPOpt(opt floatingips.FloatingIPOpt) error {
+	var validationErr selvpcclient.ValidationError
+	v.checkRegion(&validationErr, "region", opt.Region)
+
+	return validationErr.Err()
+}
+
+// ValidateSubnetOpts checks that all subnets can be created in their regions.
+func (v *Validator) ValidateSubnetOpts(opts subnets.SubnetOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, opt := range opts.Subnets {
+		validationErr.Merge(fmt.Sprintf("subnets[%d]", i), v.ValidateSubnetOpt(opt))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateSubnetOpt checks that the region exists and the subnet with the
+// provided type and prefix length is available in it.
+func (v *Validator) ValidateSubnetOpt(opt subnets.SubnetOpt) error {
+	var validationErr selvpcclient.ValidationError
+	if !v.checkRegion(&validationErr, "region", opt.Region) {
+		return validationErr.Err()
+	}
+
+	var available []string
+	for _, subnet := range v.capabilities.Subnets {
+		if !containsString(subnet.Availability, opt.Region) {
+			continue
+		}
+		if subnet.Type == string(opt.Type) && subnet.PrefixLength == strconv.Itoa(opt.PrefixLength) {
+			return nil
+		}
+		available = append(available, subnet.Type+"/"+subnet.PrefixLength)
+	}
+	validationErr.Addf("prefix_length", "%s/%d subnets are not available in the %s region, %s",
+		opt.Type, opt.PrefixLength, opt.Region, availableValues(available))
+
+	return validationErr.Err()
+}
+
+// ValidateLicenseOpts checks that all licenses can be created in their
+// regions.
+func (v *Validator) ValidateLicenseOpts(opts licenses.LicenseOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, opt := range opts.Licenses {
+		validationErr.Merge(fmt.Sprintf("licenses[%d]", i), v.ValidateLicenseOpt(opt))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateLicenseOpt checks that the region exists and the license type is
+// available in it.
+func (v *Validator) ValidateLicenseOpt(opt licenses.LicenseOpt) error {
+	var validationErr selvpcclient.ValidationError
+	regionExists := v.checkRegion(&validationErr, "region", opt.Region)
+
+	var types []string
+	for _, license := range v.capabilities.Licenses {
+		if license.Type != string(opt.Type) {
+			types = append(types, license.Type)
+			continue
+		}
+		if regionExists && !containsString(license.Availability, opt.Region) {
+			validationErr.Addf("type", "%s license is not available in the %s region, it's available in: %s",
+				opt.Type, opt.Region, strings.Join(license.Availability, ", "))
+		}
+		return validationErr.Err()
+	}
+	validationErr.Addf("type", "%s license type is unknown, %s", opt.Type, availableValues(types))
+
+	return validationErr.Err()
+}
+
+// ValidateVRRPSubnetOpts checks that all VRRP subnets can be created in their
+// regions.
+func (v *Validator) ValidateVRRPSubnetOpts(opts vrrpsubnets.VRRPSubnetOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, opt := range opts.VRRPSubnets {
+		validationErr.Merge(fmt.Sprintf("vrrp_subnets[%d].regions", i), v.ValidateVRRPRegionOpt(opt.Regions))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateVRRPRegionOpt checks that the master and slave regions exist.
+func (v *Validator) ValidateVRRPRegionOpt(opt vrrpsubnets.VRRPRegionOpt) error {
+	var validationErr selvpcclient.ValidationError
+	v.checkRegion(&validationErr, "master", opt.Master)
+	v.checkRegion(&validationErr, "slave", opt.Slave)
+
+	return validationErr.Err()
+}
+
+// ValidateUpdateProjectQuotasOpts checks all quotas against the billing
+// resources.
+func (v *Validator) ValidateUpdateProjectQuotasOpts(opts quotas.UpdateProjectQuotasOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, quotaOpts := range opts.QuotasOpts {
+		validationErr.Merge(fmt.Sprintf("quotas[%d]", i), v.ValidateQuotaOpts(quotaOpts))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateQuotaOpts checks that the resource is quotable and all its quota
+// values match the resource quota scope.
+func (v *Validator) ValidateQuotaOpts(opts quotas.QuotaOpts) error {
+	var validationErr selvpcclient.ValidationError
+	for i, resourceQuotaOpts := range opts.ResourceQuotasOpts {
+		validationErr.Merge(fmt.Sprintf("values[%d]", i), v.ValidateResourceQuotaOpts(opts.Name, resourceQuotaOpts))
+	}
+
+	return validationErr.Err()
+}
+
+// ValidateResourceQuotaOpts checks that the resource is quotable and the
+// region and zone of the quota value match the resource quota scope:
+// both are required for the zone scope, only the region is required for the
+// region scope and none of them is allowed for the global resources.
+func (v *Validator) ValidateResourceQuotaOpts(resourceName string, opts quotas.ResourceQuotaOpts) error {
+	var validationErr selvpcclient.ValidationError
+
+	resource := v.resource(resourceName)
+	if resource == nil {
+		var names []string
+		for _, resource := range v.capabilities.Resources {
+			if resource.Quotable {
+				names = append(names, resource.Name)
+			}
+		}
+		validationErr.Addf("name", "%s resource is unknown, %s", resourceName, availableValues(names))
+		return validationErr.Err()
+	}
+	if !resource.Quotable {
+		validationErr.Addf("name", "%s resource is not quotable", resourceName)
+		return validationErr.Err()
+	}
+
+	region, zone := stringValue(opts.Region), stringValue(opts.Zone)
+	switch resource.QuotaScope {
+	case QuotaScopeZone:
+		if v.checkRegion(&validationErr, "region", region) {
+			v.checkZone(&validationErr, region, zone)
+		}
+	case QuotaScopeRegion:
+		v.checkRegion(&validationErr, "region", region)
+		if zone != "" {
+			validationErr.Addf("zone", "must be empty because %s quotas are set per region", resourceName)
+		}
+	default:
+		if region != "" {
+			validationErr.Addf("region", "must be empty because %s quotas are global", resourceName)
+		}
+		if zone != "" {
+			validationErr.Addf("zone", "must be empty because %s quotas are global", resourceName)
+		}
+	}
+
+	return validationErr.Err()
+}
+
+// checkRegion adds the error if the region doesn't exist. It returns true if
+// the region exists.
+func (v *Validator) checkRegion(validationErr *selvpcclient.ValidationError, field, name string) bool {
+	if v.region(name) != nil {
+		return true
+	}
+
+	names := make([]string, 0, len(v.capabilities.Regions))
+	for _, region := range v.capabilities.Regions {
+		names = append(names, region.Name)
+	}
+	if name == "" {
+		validationErr.Addf(field, "is required, %s", availableValues(names))
+	} else {
+		validationErr.Addf(field, "%s region doesn't exist, %s", name, availableValues(names))
+	}
+
+	return false
+}
+
+// checkZone adds the error if the zone doesn't exist in the region or it's
+// disabled.
+func (v *Validator) checkZone(validationErr *selvpcclient.ValidationError, regionName, name string) {
+	region := v.region(regionName)
+
+	var enabled []string
+	for _, zone := range region.Zones {
+		if zone.Name == name {
+			if !zone.Enabled {
+				validationErr.Addf("zone", "%s zone is disabled", name)
+			}
+			return
+		}
+		if zone.Enabled {
+			enabled = append(enabled, zone.Name)
+		}
+	}
+	if name == "" {
+		validationErr.Addf("zone", "is required, %s", availableValues(enabled))
+	} else {
+		validationErr.Addf("zone", "%s zone doesn't exist in the %s region, %s", name, regionName, availableValues(enabled))
+	}
+}
+
+// region returns the region by its name.
+func (v *Validator) region(name string) *Region {
+	for i := range v.capabilities.Regions {
+		if v.capabilities.Regions[i].Name == name {
+			return &v.capabilities.Regions[i]
+		}
+	}
+
+	return nil
+}
+
+// resource returns the billing resource by its name.
+func (v *Validator) resource(name string) *Resource {
+	for i := range v.capabilities.Resources {
+		if v.capabilities.Resources[i].Name == name {
+			return &v.capabilities.Resources[i]
+		}
+	}
+
+	return nil
+}
+
+// availableValues returns the sorted list of available values for the error
+// message.
+func availableValues(values []string) string {
+	if len(values) == 0 {
+		return "nothing is available"
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	return "available: " + strings.Join(sorted, ", ")
+}
+
+// containsString checks if the slice contains the string.
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stringValue returns the value of the string pointer or an empty string.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
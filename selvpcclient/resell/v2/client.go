@@ -31,3 +31,67 @@ func NewV2ResellClientWithEndpoint(tokenID, endpoint string) *selvpcclient.Servi
 
 	return resellClient
 }
+
+// NewClient initializes a new Resell client for the V2 API with the provided
+// options. Either token or token source is required, other parameters have the same defaults as in
+// the NewV2ResellClient.
+//
+//	resellClient, err := v2.NewClient(
+//		v2.WithToken(token),
+//		v2.WithTimeout(30*time.Second),
+//		v2.WithRetryPolicy(selvpcclient.DefaultRetryPolicy()),
+//	)
+func NewClient(opts ...Option) (*selvpcclient.ServiceClient, error) {
+	options := &clientOptions{
+		endpoint: resell.Endpoint + "/" + APIVersion,
+		timeouts: selvpcclient.DefaultHTTPTimeouts(),
+	}
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, err
+		}
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	userAgent := resell.UserAgent
+	if options.userAgentSuffix != "" {
+		userAgent += " " + options.userAgentSuffix
+	}
+
+	return &selvpcclient.ServiceClient{
+		HTTPClient:       options.buildHTTPClient(),
+		Endpoint:         options.endpoint,
+		TokenID:          options.tokenID,
+		TokenSource:      options.tokenSource,
+		UserAgent:        userAgent,
+		RetryPolicy:      options.retryPolicy,
+		RateLimiter:      options.rateLimiter,
+		CircuitBreaker:   options.circuitBreaker,
+		Logger:           options.logger,
+		Debug:            options.debug,
+		Tracer:           options.tracer,
+		Metrics:          options.metrics,
+		DryRun:           options.dryRun,
+		MaxResponseSize:  options.maxResponseSize,
+		AcceptGzip:       options.acceptGzip,
+		KeepResponseBody: options.keepResponseBody,
+	}, nil
+}
+
+// NewClientFromConfig initializes a new Resell client for the V2 API with the
+// config that is loaded by the selvpcclient.LoadConfig from the environment
+// and the profile file. The provided options override the config values.
+//
+//	resellClient, err := v2.NewClientFromConfig(selvpcclient.LoadConfigOpts{
+//		Profile: "staging",
+//	})
+func NewClientFromConfig(loadOpts selvpcclient.LoadConfigOpts, opts ...Option) (*selvpcclient.ServiceClient, error) {
+	cfg, err := selvpcclient.LoadConfig(loadOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(append([]Option{WithConfig(cfg)}, opts...)...)
+}
@@ -1,7 +1,11 @@
 package v2
 
 import (
+	"io/ioutil"
+	"log"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell"
@@ -41,3 +45,237 @@ func TestNewV2ResellClientWithEndpoint(t *testing.T) {
 
 	testutils.CompareClients(t, expected, actual)
 }
+
+func TestNewClient(t *testing.T) {
+	token := "fakeID"
+	retryPolicy := selvpcclient.DefaultRetryPolicy()
+	logger := log.New(ioutil.Discard, "", 0)
+
+	expected := &selvpcclient.ServiceClient{
+		Endpoint:  "http://example.org/resell/v2",
+		TokenID:   token,
+		UserAgent: resell.UserAgent + " my-tool/1.0",
+	}
+
+	actual, err := NewClient(
+		WithToken(token),
+		WithEndpoint("http://example.org/resell/v2/"),
+		WithUserAgentSuffix("my-tool/1.0"),
+		WithTimeout(10*time.Second),
+		WithRetryPolicy(retryPolicy),
+		WithLogger(logger),
+		WithDebug(),
+		WithMaxResponseSize(1<<20),
+		WithGzip(),
+		WithKeepResponseBody(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.HTTPClient == nil {
+		t.Fatal("expected initialised HTTPClient but it's nil")
+	}
+	if actual.HTTPClient.Timeout != 10*time.Second {
+		t.Errorf("expected 10s HTTP client timeout, but got %s", actual.HTTPClient.Timeout)
+	}
+	if actual.RetryPolicy != retryPolicy {
+		t.Errorf("expected provided retry policy, but got %v", actual.RetryPolicy)
+	}
+	if actual.Logger != logger {
+		t.Errorf("expected provided logger, but got %v", actual.Logger)
+	}
+	if !actual.Debug {
+		t.Error("expected enabled debug mode")
+	}
+	if actual.MaxResponseSize != 1<<20 {
+		t.Errorf("expected 1048576 max response size, but got %d", actual.MaxResponseSize)
+	}
+	if !actual.AcceptGzip {
+		t.Error("expected enabled gzip compression")
+	}
+	if !actual.KeepResponseBody {
+		t.Error("expected kept response bodies")
+	}
+
+	testutils.CompareClients(t, expected, actual)
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	actual, err := NewClient(WithToken("fakeID"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := NewV2ResellClient("fakeID")
+	if actual.HTTPClient.Timeout != expected.HTTPClient.Timeout {
+		t.Errorf("expected %s HTTP client timeout, but got %s", expected.HTTPClient.Timeout, actual.HTTPClient.Timeout)
+	}
+	if actual.RetryPolicy != nil {
+		t.Errorf("expected no retry policy by default, but got %v", actual.RetryPolicy)
+	}
+
+	testutils.CompareClients(t, expected, actual)
+}
+
+func TestNewClientTokenSource(t *testing.T) {
+	source := selvpcclient.StaticTokenSource("fakeID")
+	actual, err := NewClient(WithTokenSource(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.TokenSource != source {
+		t.Fatalf("expected provided token source, but got %v", actual.TokenSource)
+	}
+}
+
+func TestNewClientCustomHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	actual, err := NewClient(WithToken("fakeID"), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.HTTPClient != httpClient {
+		t.Fatal("expected provided HTTP client")
+	}
+
+	transport := &http.Transport{}
+	actual, err = NewClient(WithToken("fakeID"), WithTransport(transport), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.HTTPClient.Transport != transport {
+		t.Fatal("expected provided HTTP transport")
+	}
+}
+
+func TestNewClientConfig(t *testing.T) {
+	cfg := &selvpcclient.Config{
+		Token:    "fakeID",
+		Endpoint: "http://example.org/resell/v2",
+		Timeout:  10 * time.Second,
+		Retries:  2,
+	}
+	actual, err := NewClient(WithConfig(cfg), WithUserAgentSuffix("my-tool/1.0"), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := NewV2ResellClientWithEndpoint("fakeID", "http://example.org/resell/v2")
+	expected.UserAgent += " my-tool/1.0"
+	if actual.HTTPClient.Timeout != time.Second {
+		t.Errorf("expected overridden 1s HTTP client timeout, but got %s", actual.HTTPClient.Timeout)
+	}
+	if actual.RetryPolicy == nil || actual.RetryPolicy.MaxAttempts != 3 {
+		t.Errorf("expected retry policy with 3 max attempts, but got %v", actual.RetryPolicy)
+	}
+
+	testutils.CompareClients(t, expected, actual)
+}
+
+func TestNewClientConfigTokenSource(t *testing.T) {
+	cfg := &selvpcclient.Config{
+		Token:    "fakeID",
+		Endpoint: "http://example.org/resell/v2",
+	}
+	source := selvpcclient.StaticTokenSource("sourceID")
+	actual, err := NewClient(WithConfig(cfg), WithTokenSource(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual.TokenSource != source {
+		t.Fatalf("expected provided token source, but got %v", actual.TokenSource)
+	}
+	if actual.TokenID != "" {
+		t.Fatalf("expected no config token, but got %s", actual.TokenID)
+	}
+	if actual.Endpoint != cfg.Endpoint {
+		t.Fatalf("expected config endpoint %s, but got %s", cfg.Endpoint, actual.Endpoint)
+	}
+
+	// Explicit static token still conflicts with the token source.
+	if _, err := NewClient(WithConfig(cfg), WithToken("fakeID"), WithTokenSource(source)); err == nil {
+		t.Fatal("expected error for the static token with the token source")
+	}
+}
+
+func TestNewClientInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "no token",
+			opts: []Option{WithEndpoint("http://example.org")},
+		},
+		{
+			name: "empty token",
+			opts: []Option{WithToken("")},
+		},
+		{
+			name: "relative endpoint",
+			opts: []Option{WithToken("fakeID"), WithEndpoint("example.org/resell")},
+		},
+		{
+			name: "non-positive timeout",
+			opts: []Option{WithToken("fakeID"), WithTimeout(0)},
+		},
+		{
+			name: "HTTP client with timeout",
+			opts: []Option{WithToken("fakeID"), WithHTTPClient(&http.Client{}), WithTimeout(time.Second)},
+		},
+		{
+			name: "HTTP client with transport",
+			opts: []Option{WithToken("fakeID"), WithHTTPClient(&http.Client{}), WithTransport(&http.Transport{})},
+		},
+		{
+			name: "transport with dial timeout",
+			opts: []Option{WithToken("fakeID"), WithTransport(&http.Transport{}), WithDialTimeout(time.Second)},
+		},
+		{
+			name: "negative retry attempts",
+			opts: []Option{WithToken("fakeID"), WithRetryPolicy(&selvpcclient.RetryPolicy{MaxAttempts: -1})},
+		},
+		{
+			name: "token with token source",
+			opts: []Option{WithToken("fakeID"), WithTokenSource(selvpcclient.StaticTokenSource("fakeID"))},
+		},
+		{
+			name: "debug without logger",
+			opts: []Option{WithToken("fakeID"), WithDebug()},
+		},
+		{
+			name: "nil logger",
+			opts: []Option{WithToken("fakeID"), WithLogger(nil)},
+		},
+		{
+			name: "nil circuit breaker",
+			opts: []Option{WithToken("fakeID"), WithCircuitBreaker(nil)},
+		},
+		{
+			name: "nil config",
+			opts: []Option{WithConfig(nil)},
+		},
+		{
+			name: "config without token",
+			opts: []Option{WithConfig(&selvpcclient.Config{Endpoint: "http://example.org"})},
+		},
+		{
+			name: "nil dry-run plan",
+			opts: []Option{WithToken("fakeID"), WithDryRun(nil)},
+		},
+		{
+			name: "zero max response size",
+			opts: []Option{WithToken("fakeID"), WithMaxResponseSize(0)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		client, err := NewClient(testCase.opts...)
+		if err == nil {
+			t.Errorf("%s: expected error from the NewClient", testCase.name)
+		}
+		if client != nil {
+			t.Errorf("%s: expected no client from the NewClient", testCase.name)
+		}
+	}
+}
@@ -20,6 +20,28 @@ Example of getting all floating ips
     fmt.Println(floatingIP)
   }
 
+Example of getting active floating ips of a single project
+
+  listOpts := floatingips.ListOpts{
+    ProjectID: "49338ac045f448e294b25d013f890317",
+    Status:    floatingips.StatusActive,
+  }
+  projectFloatingIPs, _, err := floatingips.List(ctx, resellClient, listOpts)
+  if err != nil {
+    log.Fatal(err)
+  }
+
+Example of iterating over floating ips page by page
+
+  iterator := floatingips.NewIterator(resellClient, floatingips.ListOpts{Limit: 100})
+  defer iterator.Close()
+  for iterator.Next(ctx) {
+    fmt.Println(iterator.Value())
+  }
+  if err := iterator.Err(); err != nil {
+    log.Fatal(err)
+  }
+
 Example of creating floating ips in a project
 
   newFloatingIPsOpts := floatingips.FloatingIPOpts{
@@ -39,6 +61,22 @@ Example of creating floating ips in a project
     fmt.Println(newFloatingIPs)
   }
 
+Example of creating floating ips without a double allocation on timeouts
+
+  newFloatingIPs, _, err := floatingips.CreateWithReconciliation(ctx, resellClient, projectID, newFloatingIPsOpts, nil)
+  if err != nil {
+    log.Fatal(err)
+  }
+
+Example of waiting until a floating ip becomes usable
+
+  waitOpts := selvpcclient.WaitOpts{Interval: 2 * time.Second, Timeout: 5 * time.Minute}
+  floatingIP, err := floatingips.WaitForStatus(ctx, resellClient, newFloatingIPs[0].ID, waitOpts, floatingips.StatusActive, floatingips.StatusDown)
+  if err != nil {
+    log.Fatal(err)
+  }
+  fmt.Println(floatingIP.FloatingIPAddress)
+
 Example of deleting a single floating ip
 
   _, err = floatingips.Delete(ctx, resellClient, "412a04ba-4cb2-4823-abd1-fcd48952b882")
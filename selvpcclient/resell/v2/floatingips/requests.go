@@ -5,16 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
 
 const resourceURL = "floatingips"
 
+// failedStatuses contains terminal statuses of the failed resources.
+var failedStatuses = []string{string(StatusError)}
+
 // Get returns a single floating ip by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*FloatingIP, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,15 +42,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*F
 
 // List gets a list of floating ips in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts) ([]*FloatingIP, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
-
-	queryParams, err := selvpcclient.BuildQueryParameters(opts)
+	url, err := client.BuildURLWithQuery(opts, resourceURL)
 	if err != nil {
 		return nil, nil, err
 	}
-	if queryParams != "" {
-		url = strings.Join([]string{url, queryParams}, "?")
-	}
 
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -64,18 +64,73 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts
 		return nil, responseResult, err
 	}
 
-	return result.FloatingIPs, responseResult, nil
+	filtered := make([]*FloatingIP, 0, len(result.FloatingIPs))
+	for _, floatingIP := range result.FloatingIPs {
+		if opts.matches(floatingIP) {
+			filtered = append(filtered, floatingIP)
+		}
+	}
+
+	return filtered, responseResult, nil
+}
+
+// Iterator iterates over floating ips like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over floating ips that match
+// the options. Pages of the opts.Limit size are requested if the limit is set.
+func NewIterator(client *selvpcclient.ServiceClient, opts ListOpts) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(marker string) (string, error) {
+			pageOpts := opts
+			if marker != "" {
+				pageOpts.Marker = marker
+			}
+			return client.BuildURLWithQuery(pageOpts, resourceURL)
+		}),
+		Key: "floatingips",
+		New: func() interface{} {
+			return &FloatingIP{}
+		},
+		Marker: func(value interface{}) string {
+			return value.(*FloatingIP).ID
+		},
+		Limit: opts.Limit,
+		Filter: func(value interface{}) bool {
+			return opts.matches(value.(*FloatingIP))
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current floating ip.
+func (iterator *Iterator) Value() *FloatingIP {
+	value, _ := iterator.ListIterator.Value().(*FloatingIP)
+
+	return value
 }
 
 // Create requests a creation of the floating ip in the specified project.
+// The Idempotency-Key header is sent if the context contains a key that is
+// set by the selvpcclient.WithIdempotencyKey.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts FloatingIPOpts) ([]*FloatingIP, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	createFloatingIPOpts := &createOpts
 	requestBody, err := json.Marshal(createFloatingIPOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", projectID}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", projectID)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -96,9 +151,57 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID s
 	return result.FloatingIPs, responseResult, nil
 }
 
+// CreateWithReconciliation requests a creation of the floating ips like the
+// Create with the selvpcclient.CreateWithReconciliation, floating ips are
+// matched by their regions. Pass floating ips of the project from a previous
+// List as the existing ones to skip the List before the creation.
+func CreateWithReconciliation(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts FloatingIPOpts, existing []*FloatingIP) ([]*FloatingIP, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	requested := make(map[string]int, len(createOpts.FloatingIPs))
+	for _, opt := range createOpts.FloatingIPs {
+		requested[opt.Region] += opt.Quantity
+	}
+	var createdFloatingIPs []*FloatingIP
+	reconciled, responseResult, err := selvpcclient.CreateWithReconciliation(ctx, selvpcclient.ReconcileOpts{
+		List: func(ctx context.Context) ([]selvpcclient.ReconcileItem, error) {
+			floatingIPs, _, err := List(ctx, client, ListOpts{ProjectID: projectID})
+			if err != nil {
+				return nil, err
+			}
+			return reconcileItems(projectID, floatingIPs), nil
+		},
+		Create: func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+			var (
+				responseResult *selvpcclient.ResponseResult
+				err            error
+			)
+			createdFloatingIPs, responseResult, err = Create(ctx, client, projectID, createOpts)
+			return responseResult, err
+		},
+		Requested: requested,
+		Existing:  reconcileItems(projectID, existing),
+	})
+	if reconciled == nil {
+		return createdFloatingIPs, responseResult, err
+	}
+
+	reconciledFloatingIPs := make([]*FloatingIP, 0, len(reconciled))
+	for _, value := range reconciled {
+		reconciledFloatingIPs = append(reconciledFloatingIPs, value.(*FloatingIP))
+	}
+
+	return reconciledFloatingIPs, responseResult, nil
+}
+
 // Delete deletes a single floating ip by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
@@ -108,3 +211,58 @@ func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string)
 	}
 	return responseResult, err
 }
+
+// WaitForStatus polls the floating ip until it reaches one of the provided
+// statuses and returns its last state. The selvpcclient.UnexpectedStatusError
+// is returned if the floating ip reaches the ERROR status or is deleted.
+func WaitForStatus(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts, statuses ...Status) (*FloatingIP, error) {
+	target := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		target = append(target, string(status))
+	}
+	value, err := selvpcclient.WaitForResource(ctx, opts, resourceURL, id, getFunc(client, id), target, failedStatuses)
+	floatingIP, _ := value.(*FloatingIP)
+
+	return floatingIP, err
+}
+
+// WaitForDeleted polls the floating ip until it doesn't exist anymore.
+func WaitForDeleted(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts) error {
+	_, err := WaitForStatus(ctx, client, id, opts, Status(selvpcclient.StatusDeleted))
+
+	return err
+}
+
+// getFunc returns the function that gets the floating ip for the
+// selvpcclient.WaitForResource.
+func getFunc(client *selvpcclient.ServiceClient, id string) selvpcclient.ResourceGetFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		floatingIP, _, err := Get(ctx, client, id)
+		if err != nil || floatingIP == nil {
+			return nil, "", err
+		}
+
+		return floatingIP, string(floatingIP.Status), nil
+	}
+}
+
+// reconcileItems converts floating ips of the project for the
+// selvpcclient.CreateWithReconciliation. It returns nil for nil floating ips.
+func reconcileItems(projectID string, floatingIPs []*FloatingIP) []selvpcclient.ReconcileItem {
+	if floatingIPs == nil {
+		return nil
+	}
+	items := make([]selvpcclient.ReconcileItem, 0, len(floatingIPs))
+	for _, floatingIP := range floatingIPs {
+		if floatingIP.ProjectID != projectID {
+			continue
+		}
+		items = append(items, selvpcclient.ReconcileItem{
+			ID:    floatingIP.ID,
+			Key:   floatingIP.Region,
+			Value: floatingIP,
+		})
+	}
+
+	return items
+}
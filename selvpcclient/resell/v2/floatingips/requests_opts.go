@@ -1,5 +1,11 @@
 package floatingips
 
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
 // FloatingIPOpts represents options for the floating ips Create request.
 type FloatingIPOpts struct {
 	// FloatingIPs represents options for all floating ips.
@@ -16,6 +22,56 @@ type FloatingIPOpt struct {
 }
 
 // ListOpts represents options for the floating ips List request.
+// Filters are sent as query parameters and are also applied to the
+// response in case the API ignores some of them.
 type ListOpts struct {
+	// Detailed requests additional details like associated servers.
 	Detailed bool `param:"detailed"`
+
+	// Limit sets the maximum number of floating ips in the response.
+	// It's used as the page size by the Iterator.
+	Limit int `param:"limit"`
+
+	// Marker sets the ID of the last floating ip of the previous page.
+	Marker string `param:"marker"`
+
+	// ProjectID filters floating ips by the project.
+	ProjectID string `param:"project_id"`
+
+	// Region filters floating ips by the region.
+	Region string `param:"region"`
+
+	// Status filters floating ips by the status.
+	Status Status `param:"status"`
+}
+
+// Validate checks that every floating ip has a region and a positive quantity.
+func (opts *FloatingIPOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.FloatingIPs) == 0 {
+		validationErr.Add("floatingips", "must contain at least one floating ip")
+	}
+	for i, opt := range opts.FloatingIPs {
+		field := fmt.Sprintf("floatingips[%d]", i)
+		if opt.Region == "" {
+			validationErr.Add(field+".region", "is required")
+		}
+		if opt.Quantity < 1 {
+			validationErr.Add(field+".quantity", "must be positive")
+		}
+	}
+
+	return validationErr.Err()
+}
+
+// matches checks if the floating ip satisfies all set filters.
+func (opts ListOpts) matches(floatingIP *FloatingIP) bool {
+	switch {
+	case opts.ProjectID != "" && opts.ProjectID != floatingIP.ProjectID,
+		opts.Region != "" && opts.Region != floatingIP.Region,
+		opts.Status != "" && opts.Status != floatingIP.Status:
+		return false
+	}
+
+	return true
 }
@@ -24,7 +24,7 @@ type FloatingIP struct {
 	Region string `json:"region"`
 
 	// Status represents a current status of the floating ip.
-	Status string `json:"status"`
+	Status Status `json:"status"`
 
 	// Servers contains info about servers to which floating ip is associated to.
 	Servers []servers.Server `json:"servers"`
@@ -41,3 +41,34 @@ type LoadBalancer struct {
 	// Name is a human-readable name of the load balancer.
 	Name string `json:"name"`
 }
+
+// IsAttached checks if the floating ip is associated to a port of a server
+// or a load balancer.
+func (floatingIP *FloatingIP) IsAttached() bool {
+	return floatingIP.PortID != "" || len(floatingIP.Servers) > 0
+}
+
+// Status represents a status of the floating ip.
+type Status string
+
+const (
+	// StatusActive represents the floating ip that is associated to a port.
+	StatusActive Status = "ACTIVE"
+
+	// StatusDown represents the floating ip that isn't associated to any port.
+	StatusDown Status = "DOWN"
+
+	// StatusError represents the floating ip that failed to be created or updated.
+	StatusError Status = "ERROR"
+)
+
+// IsKnown checks if the status is one of the statuses that are known to the
+// client. Unknown statuses returned by the API are kept as is.
+func (s Status) IsKnown() bool {
+	switch s {
+	case StatusActive, StatusDown, StatusError:
+		return true
+	}
+
+	return false
+}
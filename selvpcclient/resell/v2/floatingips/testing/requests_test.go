@@ -2,10 +2,15 @@ package testing
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/floatingips"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
@@ -479,3 +484,433 @@ func TestDeleteFloatingIPTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestDeleteFloatingIPNotFound(t *testing.T) {
+	endpointCalled := false
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testutils.HandleReqWithoutBody(t, &testutils.HandleReqOpts{
+		Mux:      testEnv.Mux,
+		URL:      "/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd",
+		Method:   http.MethodDelete,
+		Status:   http.StatusNotFound,
+		CallFlag: &endpointCalled,
+	})
+
+	ctx := context.Background()
+	_, err := floatingips.Delete(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd")
+
+	if !endpointCalled {
+		t.Fatal("endpoint wasn't called")
+	}
+	if !selvpcclient.IsNotFound(err) {
+		t.Fatalf("expected not found error from the Delete method, but got %v", err)
+	}
+	if selvpcclient.IsQuotaExceeded(err) {
+		t.Fatal("expected not found error not to be reported as quota exceeded")
+	}
+}
+
+// newReconciliationTestEnv returns the test environment where the List
+// returns no floating ips for the first time and a single one afterwards,
+// and the Create fails with the timeout.
+func newReconciliationTestEnv(t *testing.T, listCalls, createCalls *int) *testutils.TestEnv {
+	testEnv := testutils.SetupTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips", func(w http.ResponseWriter, r *http.Request) {
+		*listCalls++
+		w.Header().Add("Content-Type", "application/json")
+		if *listCalls == 1 {
+			fmt.Fprint(w, `{"floatingips": []}`)
+			return
+		}
+		fmt.Fprint(w, TestListFloatingIPsSingleResponseRaw)
+	})
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/projects/49338ac045f448e294b25d013f890317", func(w http.ResponseWriter, r *http.Request) {
+		*createCalls++
+		if r.Header.Get(selvpcclient.IdempotencyKeyHeader) == "" {
+			t.Error("expected the Idempotency-Key header")
+		}
+		w.WriteHeader(http.StatusGatewayTimeout)
+	})
+
+	return testEnv
+}
+
+func TestCreateFloatingIPsWithReconciliation(t *testing.T) {
+	listCalls, createCalls := 0, 0
+	testEnv := newReconciliationTestEnv(t, &listCalls, &createCalls)
+	defer testEnv.TearDownTestEnv()
+
+	ctx := context.Background()
+	createOpts := floatingips.FloatingIPOpts{
+		FloatingIPs: []floatingips.FloatingIPOpt{{Region: "ru-2", Quantity: 1}},
+	}
+	actual, _, err := floatingips.CreateWithReconciliation(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", createOpts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TestListFloatingIPsSingleResponse
+
+	if createCalls != 1 {
+		t.Fatalf("expected 1 create request, but got %d", createCalls)
+	}
+	if listCalls != 2 {
+		t.Fatalf("expected 2 list requests, but got %d", listCalls)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestCreateFloatingIPsWithReconciliationExisting(t *testing.T) {
+	// The first List is skipped, so the List always returns the new floating ip.
+	listCalls, createCalls := 1, 0
+	testEnv := newReconciliationTestEnv(t, &listCalls, &createCalls)
+	defer testEnv.TearDownTestEnv()
+
+	ctx := context.Background()
+	createOpts := floatingips.FloatingIPOpts{
+		FloatingIPs: []floatingips.FloatingIPOpt{{Region: "ru-2", Quantity: 1}},
+	}
+	actual, _, err := floatingips.CreateWithReconciliation(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", createOpts, []*floatingips.FloatingIP{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listCalls != 2 {
+		t.Fatalf("expected only the reconciliation list request, but got %d", listCalls-1)
+	}
+	if !reflect.DeepEqual(actual, TestListFloatingIPsSingleResponse) {
+		t.Fatalf("expected %#v, but got %#v", TestListFloatingIPsSingleResponse, actual)
+	}
+}
+
+func TestCreateFloatingIPsWithReconciliationQuantityMismatch(t *testing.T) {
+	listCalls, createCalls := 0, 0
+	testEnv := newReconciliationTestEnv(t, &listCalls, &createCalls)
+	defer testEnv.TearDownTestEnv()
+
+	ctx := context.Background()
+	actual, _, err := floatingips.CreateWithReconciliation(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", TestCreateFloatingIPOpts, nil)
+	if !selvpcclient.IsAmbiguousError(err) {
+		t.Fatalf("expected the ambiguous error, but got %v", err)
+	}
+	if actual != nil {
+		t.Fatalf("expected no floating ips, but got %#v", actual)
+	}
+	if createCalls != 1 {
+		t.Fatalf("expected 1 create request, but got %d", createCalls)
+	}
+}
+
+func TestCreateFloatingIPsWithReconciliationRepeat(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"floatingips": []}`)
+	})
+	var keys []string
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/projects/49338ac045f448e294b25d013f890317", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(selvpcclient.IdempotencyKeyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, TestCreateFloatingIPResponseRaw)
+	})
+
+	ctx := selvpcclient.WithIdempotencyKey(context.Background(), "f6f9ec64-5ba5-4b3a-8d2a-2f3e5bd0c0f1")
+	actual, _, err := floatingips.CreateWithReconciliation(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", TestCreateFloatingIPOpts, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TestCreateFloatingIPResponse
+
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 create requests, but got %d", len(keys))
+	}
+	for _, key := range keys {
+		if key != "f6f9ec64-5ba5-4b3a-8d2a-2f3e5bd0c0f1" {
+			t.Fatalf("expected the idempotency key from the context, but got %q", key)
+		}
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestWaitForFloatingIPStatus(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	getCalls := 0
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd", func(w http.ResponseWriter, r *http.Request) {
+		getCalls++
+		w.Header().Add("Content-Type", "application/json")
+		if getCalls == 1 {
+			fmt.Fprint(w, strings.Replace(TestGetFloatingIPResponseRaw, `"status": "ACTIVE"
+    }`, `"status": "DOWN"
+    }`, 1))
+			return
+		}
+		fmt.Fprint(w, TestGetFloatingIPResponseRaw)
+	})
+
+	ctx := context.Background()
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	actual, err := floatingips.WaitForStatus(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd", opts, floatingips.StatusActive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TestGetFloatingIPResponse
+
+	if getCalls != 2 {
+		t.Fatalf("expected 2 get requests, but got %d", getCalls)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestWaitForFloatingIPStatusError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, strings.Replace(TestGetFloatingIPResponseRaw, `"status": "ACTIVE"
+    }`, `"status": "ERROR"
+    }`, 1))
+	})
+
+	ctx := context.Background()
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	actual, err := floatingips.WaitForStatus(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd", opts, floatingips.StatusActive, floatingips.StatusDown)
+
+	var statusErr *selvpcclient.UnexpectedStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected UnexpectedStatusError, but got %v", err)
+	}
+	if statusErr.Status != "ERROR" {
+		t.Fatalf("expected the ERROR status, but got %s", statusErr.Status)
+	}
+	if actual == nil || actual.Status != floatingips.StatusError {
+		t.Fatalf("expected the floating ip in the ERROR status, but got %#v", actual)
+	}
+}
+
+func TestWaitForFloatingIPStatusTransientError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	getCalls := 0
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd", func(w http.ResponseWriter, r *http.Request) {
+		getCalls++
+		if getCalls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, TestGetFloatingIPResponseRaw)
+	})
+
+	ctx := context.Background()
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	actual, err := floatingips.WaitForStatus(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd", opts, floatingips.StatusActive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getCalls != 2 {
+		t.Fatalf("expected 2 get requests, but got %d", getCalls)
+	}
+	if !reflect.DeepEqual(actual, TestGetFloatingIPResponse) {
+		t.Fatalf("expected %#v, but got %#v", TestGetFloatingIPResponse, actual)
+	}
+}
+
+func TestWaitForFloatingIPStatusNoResource(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx := context.Background()
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	actual, err := floatingips.WaitForStatus(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd", opts, floatingips.StatusActive)
+	if !errors.Is(err, selvpcclient.ErrWaitNoResource) {
+		t.Fatalf("expected ErrWaitNoResource, but got %v", err)
+	}
+	if actual != nil {
+		t.Fatalf("expected no floating ip, but got %#v", actual)
+	}
+}
+
+func TestWaitForFloatingIPDeleted(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	getCalls := 0
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/5232d5f3-4950-454b-bd41-78c5295622cd", func(w http.ResponseWriter, r *http.Request) {
+		getCalls++
+		if getCalls < 3 {
+			w.Header().Add("Content-Type", "application/json")
+			fmt.Fprint(w, TestGetFloatingIPResponseRaw)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	err := floatingips.WaitForDeleted(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getCalls != 3 {
+		t.Fatalf("expected 3 get requests, but got %d", getCalls)
+	}
+}
+
+func TestFloatingIPIsAttached(t *testing.T) {
+	testCases := []struct {
+		name       string
+		floatingIP *floatingips.FloatingIP
+		expected   bool
+	}{
+		{
+			name:       "with port and servers",
+			floatingIP: TestGetFloatingIPResponse,
+			expected:   true,
+		},
+		{
+			name:       "with port of load balancer",
+			floatingIP: TestGetFloatingIPResponseWithLB,
+			expected:   true,
+		},
+		{
+			name:       "without port",
+			floatingIP: TestListFloatingIPsSingleResponse[0],
+			expected:   false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := testCase.floatingIP.IsAttached(); actual != testCase.expected {
+				t.Fatalf("expected %t, but got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFloatingIPStatusIsKnown(t *testing.T) {
+	for _, status := range []floatingips.Status{floatingips.StatusActive, floatingips.StatusDown, floatingips.StatusError} {
+		if !status.IsKnown() {
+			t.Errorf("expected %s status to be known", status)
+		}
+	}
+	if floatingips.Status("MIGRATING").IsKnown() {
+		t.Error("expected MIGRATING status to be unknown")
+	}
+}
+
+func TestListFloatingIPsWithFilters(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var query string
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		// The whole domain is returned to check the client-side filtering.
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, TestListFloatingIPsResponseRaw)
+	})
+
+	ctx := context.Background()
+	opts := floatingips.ListOpts{
+		ProjectID: "9c97bdc75295493096cf5edcb8c37933",
+		Status:    floatingips.StatusActive,
+	}
+	actual, _, err := floatingips.List(ctx, testEnv.Client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedQuery := "project_id=9c97bdc75295493096cf5edcb8c37933&status=ACTIVE"
+	if query != expectedQuery {
+		t.Fatalf("expected %q query, but got %q", expectedQuery, query)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("expected 1 floating ip, but got %d", len(actual))
+	}
+	if actual[0].ID != "8233f12e-c47e-4f1c-953a-1ecd322a7119" {
+		t.Fatalf("expected 8233f12e-c47e-4f1c-953a-1ecd322a7119 floating ip, but got %s", actual[0].ID)
+	}
+}
+
+func TestFloatingIPsIterator(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var queries []string
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Add("Content-Type", "application/json")
+		if r.URL.Query().Get("marker") == "" {
+			fmt.Fprint(w, `{"floatingips": [
+				{"id": "5232d5f3-4950-454b-bd41-78c5295622cd", "region": "ru-2", "status": "ACTIVE"},
+				{"id": "94425a6e-19cd-412d-9710-ff40b34a78f4", "region": "ru-2", "status": "DOWN"}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"floatingips": [
+			{"id": "8233f12e-c47e-4f1c-953a-1ecd322a7119", "region": "ru-2", "status": "ACTIVE"}
+		]}`)
+	})
+
+	ctx := context.Background()
+	iterator := floatingips.NewIterator(testEnv.Client, floatingips.ListOpts{
+		Limit:  2,
+		Status: floatingips.StatusActive,
+	})
+	defer iterator.Close()
+
+	var actual []string
+	for iterator.Next(ctx) {
+		actual = append(actual, iterator.Value().ID)
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"5232d5f3-4950-454b-bd41-78c5295622cd", "8233f12e-c47e-4f1c-953a-1ecd322a7119"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, but got %v", expected, actual)
+	}
+	expectedQueries := []string{
+		"limit=2&status=ACTIVE",
+		"limit=2&marker=94425a6e-19cd-412d-9710-ff40b34a78f4&status=ACTIVE",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Fatalf("expected %v queries, but got %v", expectedQueries, queries)
+	}
+}
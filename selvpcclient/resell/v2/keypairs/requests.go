@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -14,7 +13,10 @@ const resourceURL = "keypairs"
 
 // List gets a list of keypairs in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Keypair, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -35,8 +37,40 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Keypair,
 	return result.Keypairs, responseResult, nil
 }
 
+// Iterator iterates over keypairs like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over all keypairs of the
+// current domain users.
+func NewIterator(client *selvpcclient.ServiceClient) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(string) (string, error) {
+			return client.BuildURL(resourceURL)
+		}),
+		Key: "keypairs",
+		New: func() interface{} {
+			return &Keypair{}
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current keypair.
+func (iterator *Iterator) Value() *Keypair {
+	value, _ := iterator.ListIterator.Value().(*Keypair)
+
+	return value
+}
+
 // Create requests a creation of the keypar with the specified options.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts KeypairOpts) ([]*Keypair, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	// Nest create opts into additional body.
 	type nestedCreateOpts struct {
 		Keypair KeypairOpts `json:"keypair"`
@@ -49,7 +83,10 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -72,7 +109,10 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 
 // Delete deletes a single keypair by its name and user ID.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, name, userID string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, name, "users", userID}, "/")
+	url, err := client.BuildURL(resourceURL, name, "users", userID)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
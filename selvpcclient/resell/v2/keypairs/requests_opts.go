@@ -1,5 +1,11 @@
 package keypairs
 
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
 // KeypairOpts represents options for the keypair Create request.
 type KeypairOpts struct {
 	// Name contains a human-readable name for the keypair.
@@ -16,3 +22,24 @@ type KeypairOpts struct {
 	// this keypair.
 	UserID string `json:"user_id"`
 }
+
+// Validate checks that the keypair has a name, a public key and an owner.
+func (opts *KeypairOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.Name == "" {
+		validationErr.Add("name", "is required")
+	}
+	if opts.PublicKey == "" {
+		validationErr.Add("public_key", "is required")
+	}
+	if opts.UserID == "" {
+		validationErr.Add("user_id", "is required")
+	}
+	for i, region := range opts.Regions {
+		if region == "" {
+			validationErr.Add(fmt.Sprintf("regions[%d]", i), "can't be empty")
+		}
+	}
+
+	return validationErr.Err()
+}
@@ -2,10 +2,12 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"reflect"
 	"testing"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/keypairs"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
@@ -291,6 +293,50 @@ func TestDeleteKeypair(t *testing.T) {
 	}
 }
 
+func TestDeleteKeypairEscapedName(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	// Handler is set directly because the mux redirects to cleaned paths.
+	var requestURI string
+	testEnv.Server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ctx := context.Background()
+	_, err := keypairs.Delete(ctx, testEnv.Client, "my key/../1?x", "82a026cae2104e92b999dbe00cdb9435")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "/resell/v2/keypairs/my%20key%2F..%2F1%3Fx/users/82a026cae2104e92b999dbe00cdb9435"
+	if requestURI != expected {
+		t.Fatalf("expected %s request URI, but got %s", expected, requestURI)
+	}
+}
+
+func TestDeleteKeypairEmptyName(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	endpointCalled := false
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		endpointCalled = true
+	})
+
+	ctx := context.Background()
+	_, err := keypairs.Delete(ctx, testEnv.Client, "", "82a026cae2104e92b999dbe00cdb9435")
+	if !errors.Is(err, selvpcclient.ErrInvalidPathSegment) {
+		t.Fatalf("expected ErrInvalidPathSegment, but got %v", err)
+	}
+	if endpointCalled {
+		t.Fatal("expected no request with the empty name")
+	}
+}
+
 func TestDeleteKeypairHTTPError(t *testing.T) {
 	endpointCalled := false
 
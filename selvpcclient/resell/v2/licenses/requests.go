@@ -5,16 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
 
 const resourceURL = "licenses"
 
+// failedStatuses contains terminal statuses of the failed resources.
+var failedStatuses = []string{string(StatusError)}
+
 // Get returns a single license by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*License, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,15 +43,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*L
 
 // List gets a list of licenses in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts) ([]*License, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
-
-	queryParams, err := selvpcclient.BuildQueryParameters(opts)
+	url, err := client.BuildURLWithQuery(opts, resourceURL)
 	if err != nil {
 		return nil, nil, err
 	}
-	if queryParams != "" {
-		url = strings.Join([]string{url, queryParams}, "?")
-	}
 
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -64,18 +65,73 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts
 		return nil, responseResult, err
 	}
 
-	return result.Licenses, responseResult, nil
+	filtered := make([]*License, 0, len(result.Licenses))
+	for _, license := range result.Licenses {
+		if opts.matches(license) {
+			filtered = append(filtered, license)
+		}
+	}
+
+	return filtered, responseResult, nil
+}
+
+// Iterator iterates over licenses like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over licenses that match the
+// options. Pages of the opts.Limit size are requested if the limit is set.
+func NewIterator(client *selvpcclient.ServiceClient, opts ListOpts) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(marker string) (string, error) {
+			pageOpts := opts
+			if marker != "" {
+				pageOpts.Marker = marker
+			}
+			return client.BuildURLWithQuery(pageOpts, resourceURL)
+		}),
+		Key: "licenses",
+		New: func() interface{} {
+			return &License{}
+		},
+		Marker: func(value interface{}) string {
+			return strconv.Itoa(value.(*License).ID)
+		},
+		Limit: opts.Limit,
+		Filter: func(value interface{}) bool {
+			return opts.matches(value.(*License))
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current license.
+func (iterator *Iterator) Value() *License {
+	value, _ := iterator.ListIterator.Value().(*License)
+
+	return value
 }
 
 // Create requests a creation of the licenses in the specified project.
+// The Idempotency-Key header is sent if the context contains a key that is
+// set by the selvpcclient.WithIdempotencyKey.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts LicenseOpts) ([]*License, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	createLicenseOpts := &createOpts
 	requestBody, err := json.Marshal(createLicenseOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", projectID}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", projectID)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -96,9 +152,57 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID s
 	return result.Licenses, responseResult, nil
 }
 
+// CreateWithReconciliation requests a creation of the licenses like the Create
+// with the selvpcclient.CreateWithReconciliation, licenses are matched by their
+// regions and types. Pass licenses of the project from a previous List as the
+// existing ones to skip the List before the creation.
+func CreateWithReconciliation(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts LicenseOpts, existing []*License) ([]*License, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	requested := make(map[string]int, len(createOpts.Licenses))
+	for _, opt := range createOpts.Licenses {
+		requested[opt.Region+"/"+opt.Type] += opt.Quantity
+	}
+	var createdLicenses []*License
+	reconciled, responseResult, err := selvpcclient.CreateWithReconciliation(ctx, selvpcclient.ReconcileOpts{
+		List: func(ctx context.Context) ([]selvpcclient.ReconcileItem, error) {
+			licenses, _, err := List(ctx, client, ListOpts{ProjectID: projectID})
+			if err != nil {
+				return nil, err
+			}
+			return reconcileItems(projectID, licenses), nil
+		},
+		Create: func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+			var (
+				responseResult *selvpcclient.ResponseResult
+				err            error
+			)
+			createdLicenses, responseResult, err = Create(ctx, client, projectID, createOpts)
+			return responseResult, err
+		},
+		Requested: requested,
+		Existing:  reconcileItems(projectID, existing),
+	})
+	if reconciled == nil {
+		return createdLicenses, responseResult, err
+	}
+
+	reconciledLicenses := make([]*License, 0, len(reconciled))
+	for _, value := range reconciled {
+		reconciledLicenses = append(reconciledLicenses, value.(*License))
+	}
+
+	return reconciledLicenses, responseResult, nil
+}
+
 // Delete deletes a single license by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
@@ -108,3 +212,58 @@ func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string)
 	}
 	return responseResult, err
 }
+
+// WaitForStatus polls the license until it reaches one of the provided
+// statuses and returns its last state. The selvpcclient.UnexpectedStatusError
+// is returned if the license reaches the ERROR status or is deleted.
+func WaitForStatus(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts, statuses ...Status) (*License, error) {
+	target := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		target = append(target, string(status))
+	}
+	value, err := selvpcclient.WaitForResource(ctx, opts, resourceURL, id, getFunc(client, id), target, failedStatuses)
+	license, _ := value.(*License)
+
+	return license, err
+}
+
+// WaitForDeleted polls the license until it doesn't exist anymore.
+func WaitForDeleted(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts) error {
+	_, err := WaitForStatus(ctx, client, id, opts, Status(selvpcclient.StatusDeleted))
+
+	return err
+}
+
+// getFunc returns the function that gets the license for the
+// selvpcclient.WaitForResource.
+func getFunc(client *selvpcclient.ServiceClient, id string) selvpcclient.ResourceGetFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		license, _, err := Get(ctx, client, id)
+		if err != nil || license == nil {
+			return nil, "", err
+		}
+
+		return license, string(license.Status), nil
+	}
+}
+
+// reconcileItems converts licenses of the project for the
+// selvpcclient.CreateWithReconciliation. It returns nil for nil licenses.
+func reconcileItems(projectID string, licenses []*License) []selvpcclient.ReconcileItem {
+	if licenses == nil {
+		return nil
+	}
+	items := make([]selvpcclient.ReconcileItem, 0, len(licenses))
+	for _, license := range licenses {
+		if license.ProjectID != projectID {
+			continue
+		}
+		items = append(items, selvpcclient.ReconcileItem{
+			ID:    strconv.Itoa(license.ID),
+			Key:   license.Region + "/" + string(license.Type),
+			Value: license,
+		})
+	}
+
+	return items
+}
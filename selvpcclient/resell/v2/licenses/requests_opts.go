@@ -1,5 +1,11 @@
 package licenses
 
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
 // LicenseOpts represents options for the licenses Create request.
 type LicenseOpts struct {
 	// Licenses represents options for all licenses.
@@ -19,6 +25,64 @@ type LicenseOpt struct {
 }
 
 // ListOpts represents options for the licenses List request.
+// Filters are sent as query parameters and are also applied to the
+// response in case the API ignores some of them.
 type ListOpts struct {
+	// Detailed requests additional details like associated servers.
 	Detailed bool `param:"detailed"`
+
+	// Limit sets the maximum number of licenses in the response.
+	// It's used as the page size by the Iterator.
+	Limit int `param:"limit"`
+
+	// Marker sets the ID of the last license of the previous page.
+	Marker string `param:"marker"`
+
+	// ProjectID filters licenses by the project.
+	ProjectID string `param:"project_id"`
+
+	// Region filters licenses by the region.
+	Region string `param:"region"`
+
+	// Status filters licenses by the status.
+	Status Status `param:"status"`
+
+	// Type filters licenses by the type.
+	Type Type `param:"type"`
+}
+
+// Validate checks that every license has a region, a type and a positive
+// quantity.
+func (opts *LicenseOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.Licenses) == 0 {
+		validationErr.Add("licenses", "must contain at least one license")
+	}
+	for i, opt := range opts.Licenses {
+		field := fmt.Sprintf("licenses[%d]", i)
+		if opt.Region == "" {
+			validationErr.Add(field+".region", "is required")
+		}
+		if opt.Quantity < 1 {
+			validationErr.Add(field+".quantity", "must be positive")
+		}
+		if opt.Type == "" {
+			validationErr.Add(field+".type", "is required")
+		}
+	}
+
+	return validationErr.Err()
+}
+
+// matches checks if the license satisfies all set filters.
+func (opts ListOpts) matches(license *License) bool {
+	switch {
+	case opts.ProjectID != "" && opts.ProjectID != license.ProjectID,
+		opts.Region != "" && opts.Region != license.Region,
+		opts.Status != "" && opts.Status != license.Status,
+		opts.Type != "" && opts.Type != license.Type:
+		return false
+	}
+
+	return true
 }
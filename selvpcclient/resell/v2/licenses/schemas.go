@@ -17,10 +17,10 @@ type License struct {
 	Servers []servers.Server `json:"servers"`
 
 	// Status represents a current status of the license.
-	Status string `json:"status"`
+	Status Status `json:"status"`
 
 	// Type represent a license type.
-	Type string `json:"type"`
+	Type Type `json:"type"`
 
 	// NetworkID represents id of the associated network in the Networking service.
 	NetworkID string `json:"network_id"`
@@ -31,3 +31,55 @@ type License struct {
 	// PortID represents id of the associated ports in the Networking service.
 	PortID string `json:"port_id"`
 }
+
+// IsAttached checks if the license is used by a server.
+func (license *License) IsAttached() bool {
+	return license.PortID != "" || len(license.Servers) > 0
+}
+
+// Status represents a status of the license.
+type Status string
+
+const (
+	// StatusActive represents the license that is used by a server.
+	StatusActive Status = "ACTIVE"
+
+	// StatusDown represents the license that isn't used.
+	StatusDown Status = "DOWN"
+
+	// StatusError represents the license that failed to be created.
+	StatusError Status = "ERROR"
+)
+
+// IsKnown checks if the status is one of the statuses that are known to the
+// client. Unknown statuses returned by the API are kept as is.
+func (s Status) IsKnown() bool {
+	switch s {
+	case StatusActive, StatusDown, StatusError:
+		return true
+	}
+
+	return false
+}
+
+// Type represents a type of the license.
+type Type string
+
+const (
+	// TypeWindows2012Standard represents the Windows Server 2012 Standard license.
+	TypeWindows2012Standard Type = "license_windows_2012_standard"
+
+	// TypeWindows2016Standard represents the Windows Server 2016 Standard license.
+	TypeWindows2016Standard Type = "license_windows_2016_standard"
+)
+
+// IsKnown checks if the type is one of the types that are known to the
+// client. Unknown types returned by the API are kept as is.
+func (t Type) IsKnown() bool {
+	switch t {
+	case TypeWindows2012Standard, TypeWindows2016Standard:
+		return true
+	}
+
+	return false
+}
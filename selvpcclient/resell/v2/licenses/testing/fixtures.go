@@ -198,3 +198,18 @@ const TestSingleLicenseInvalidResponseRaw = `
     }
 }
 `
+
+// TestGetLicenseUnknownResponseRaw represents a raw response from the Get
+// request with the status and type that are unknown to the client.
+const TestGetLicenseUnknownResponseRaw = `
+{
+    "license": {
+        "id": 123124,
+        "project_id": "49338ac045f448e294b25d013f890317",
+        "region": "ru-2",
+        "servers": [],
+        "status": "RESERVED",
+        "type": "license_windows_2022_datacenter"
+    }
+}
+`
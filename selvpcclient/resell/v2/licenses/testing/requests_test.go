@@ -2,6 +2,7 @@ package testing
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"reflect"
 	"testing"
@@ -448,3 +449,62 @@ func TestDeleteLicenseTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestGetLicenseUnknownStatusAndType(t *testing.T) {
+	endpointCalled := false
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testutils.HandleReqWithoutBody(t, &testutils.HandleReqOpts{
+		Mux:         testEnv.Mux,
+		URL:         "/resell/v2/licenses/123124",
+		RawResponse: TestGetLicenseUnknownResponseRaw,
+		Method:      http.MethodGet,
+		Status:      http.StatusOK,
+		CallFlag:    &endpointCalled,
+	})
+
+	ctx := context.Background()
+	actual, _, err := licenses.Get(ctx, testEnv.Client, "123124")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !endpointCalled {
+		t.Fatal("endpoint wasn't called")
+	}
+	if actual.Status != "RESERVED" || actual.Status.IsKnown() {
+		t.Fatalf("expected unknown RESERVED status, but got %s", actual.Status)
+	}
+	if actual.Type != "license_windows_2022_datacenter" || actual.Type.IsKnown() {
+		t.Fatalf("expected unknown license_windows_2022_datacenter type, but got %s", actual.Type)
+	}
+	if actual.IsAttached() {
+		t.Fatal("expected the license without servers to be detached")
+	}
+
+	body, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip licenses.License
+	if err := json.Unmarshal(body, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&roundTrip, actual) {
+		t.Fatalf("expected %#v, but got %#v", actual, &roundTrip)
+	}
+}
+
+func TestLicenseStatusAndTypeIsKnown(t *testing.T) {
+	if !TestGetLicenseResponse.Status.IsKnown() {
+		t.Errorf("expected %s status to be known", TestGetLicenseResponse.Status)
+	}
+	if !TestGetLicenseResponse.Type.IsKnown() {
+		t.Errorf("expected %s type to be known", TestGetLicenseResponse.Type)
+	}
+	if !TestGetLicenseResponse.IsAttached() {
+		t.Error("expected the license with servers to be attached")
+	}
+}
@@ -0,0 +1,393 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+var (
+	errEmptyEndpoint           = errors.New("endpoint is empty")
+	errEmptyToken              = errors.New("token is empty")
+	errNilHTTPClient           = errors.New("HTTP client is nil")
+	errNilTransport            = errors.New("HTTP transport is nil")
+	errNilRetryPolicy          = errors.New("retry policy is nil")
+	errNilLogger               = errors.New("logger is nil")
+	errNilRateLimiter          = errors.New("rate limiter is nil")
+	errNilCircuitBreaker       = errors.New("circuit breaker is nil")
+	errDebugWithoutLogger      = errors.New("debug mode requires a logger")
+	errNilTracer               = errors.New("tracer is nil")
+	errNilMetricsRecorder      = errors.New("metrics recorder is nil")
+	errNilDryRunPlan           = errors.New("dry-run plan is nil")
+	errNilConfig               = errors.New("config is nil")
+	errNilTokenSource          = errors.New("token source is nil")
+	errTokenWithTokenSource    = errors.New("static token can't be used together with a token source")
+	errNegativeRetryAttempts   = errors.New("retry policy max attempts can't be negative")
+	errNonPositiveTimeout      = errors.New("timeout must be positive")
+	errZeroMaxResponseSize     = errors.New("max response size can't be zero")
+	errHTTPClientWithTransport = errors.New("custom HTTP client can't be used together with a custom transport")
+	errHTTPClientWithTimeouts  = errors.New("custom HTTP client can't be used together with custom timeouts")
+	errTransportWithTimeouts   = errors.New("custom transport can't be used together with dial and TLS handshake timeouts")
+)
+
+// Option configures the Resell client created by the NewClient.
+type Option func(*clientOptions) error
+
+// clientOptions contains parameters of the Resell client.
+type clientOptions struct {
+	endpoint          string
+	tokenID           string
+	configToken       bool
+	tokenSource       selvpcclient.TokenSource
+	httpClient        *http.Client
+	transport         http.RoundTripper
+	timeouts          selvpcclient.HTTPTimeouts
+	requestTimeout    bool
+	transportTimeouts bool
+	userAgentSuffix   string
+	retryPolicy       *selvpcclient.RetryPolicy
+	rateLimiter       *selvpcclient.RateLimiter
+	circuitBreaker    *selvpcclient.CircuitBreaker
+	logger            selvpcclient.Logger
+	debug             bool
+	tracer            selvpcclient.Tracer
+	metrics           selvpcclient.MetricsRecorder
+	dryRun            *selvpcclient.DryRunPlan
+	maxResponseSize   int64
+	acceptGzip        bool
+	keepResponseBody  bool
+}
+
+// WithEndpoint sets a custom endpoint of the Resell V2 API.
+func WithEndpoint(endpoint string) Option {
+	return func(opts *clientOptions) error {
+		if endpoint == "" {
+			return errEmptyEndpoint
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q: absolute http or https URL is required", endpoint)
+		}
+		opts.endpoint = strings.TrimSuffix(endpoint, "/")
+
+		return nil
+	}
+}
+
+// WithToken sets a static authentication token.
+func WithToken(tokenID string) Option {
+	return func(opts *clientOptions) error {
+		if tokenID == "" {
+			return errEmptyToken
+		}
+		opts.tokenID = tokenID
+		opts.configToken = false
+
+		return nil
+	}
+}
+
+// WithTokenSource sets a source of authentication tokens that is consulted
+// before every request. It replaces the token of the previous WithConfig.
+func WithTokenSource(source selvpcclient.TokenSource) Option {
+	return func(opts *clientOptions) error {
+		if source == nil {
+			return errNilTokenSource
+		}
+		opts.tokenSource = source
+		if opts.configToken {
+			opts.tokenID = ""
+			opts.configToken = false
+		}
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client that will be used to do requests.
+// It can't be used together with the WithTransport and timeout options.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(opts *clientOptions) error {
+		if httpClient == nil {
+			return errNilHTTPClient
+		}
+		opts.httpClient = httpClient
+
+		return nil
+	}
+}
+
+// WithTransport sets a custom transport for the default HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(opts *clientOptions) error {
+		if transport == nil {
+			return errNilTransport
+		}
+		opts.transport = transport
+
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of the whole HTTP request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *clientOptions) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid request timeout %s: %w", timeout, errNonPositiveTimeout)
+		}
+		opts.timeouts.Request = timeout
+		opts.requestTimeout = true
+
+		return nil
+	}
+}
+
+// WithDialTimeout sets the timeout of the connection establishment.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(opts *clientOptions) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid dial timeout %s: %w", timeout, errNonPositiveTimeout)
+		}
+		opts.timeouts.Dial = timeout
+		opts.transportTimeouts = true
+
+		return nil
+	}
+}
+
+// WithTLSHandshakeTimeout sets the timeout of the TLS handshake.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(opts *clientOptions) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid TLS handshake timeout %s: %w", timeout, errNonPositiveTimeout)
+		}
+		opts.timeouts.TLSHandshake = timeout
+		opts.transportTimeouts = true
+
+		return nil
+	}
+}
+
+// WithUserAgentSuffix appends the provided suffix to the default user agent.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(opts *clientOptions) error {
+		opts.userAgentSuffix = strings.TrimSpace(suffix)
+
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy of failed requests retries.
+func WithRetryPolicy(policy *selvpcclient.RetryPolicy) Option {
+	return func(opts *clientOptions) error {
+		if policy == nil {
+			return errNilRetryPolicy
+		}
+		if policy.MaxAttempts < 0 {
+			return errNegativeRetryAttempts
+		}
+		opts.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithRateLimiter sets the client-side limiter of the requests rate.
+func WithRateLimiter(limiter *selvpcclient.RateLimiter) Option {
+	return func(opts *clientOptions) error {
+		if limiter == nil {
+			return errNilRateLimiter
+		}
+		opts.rateLimiter = limiter
+
+		return nil
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker that rejects requests while the
+// Resell API is degraded.
+func WithCircuitBreaker(breaker *selvpcclient.CircuitBreaker) Option {
+	return func(opts *clientOptions) error {
+		if breaker == nil {
+			return errNilCircuitBreaker
+		}
+		opts.circuitBreaker = breaker
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the client internal events.
+func WithLogger(logger selvpcclient.Logger) Option {
+	return func(opts *clientOptions) error {
+		if logger == nil {
+			return errNilLogger
+		}
+		opts.logger = logger
+
+		return nil
+	}
+}
+
+// WithDebug enables logging of every request and response with the logger
+// that is set by the WithLogger. Secrets are redacted.
+func WithDebug() Option {
+	return func(opts *clientOptions) error {
+		opts.debug = true
+
+		return nil
+	}
+}
+
+// WithTracer sets the tracer that starts a span for every request.
+func WithTracer(tracer selvpcclient.Tracer) Option {
+	return func(opts *clientOptions) error {
+		if tracer == nil {
+			return errNilTracer
+		}
+		opts.tracer = tracer
+
+		return nil
+	}
+}
+
+// WithMetrics sets the recorder of the requests metrics.
+func WithMetrics(recorder selvpcclient.MetricsRecorder) Option {
+	return func(opts *clientOptions) error {
+		if recorder == nil {
+			return errNilMetricsRecorder
+		}
+		opts.metrics = recorder
+
+		return nil
+	}
+}
+
+// WithDryRun enables the dry-run mode that records mutations to the provided
+// plan instead of sending them. Functions that return created or updated
+// resources return the selvpcclient.ErrDryRun for recorded mutations.
+func WithDryRun(plan *selvpcclient.DryRunPlan) Option {
+	return func(opts *clientOptions) error {
+		if plan == nil {
+			return errNilDryRunPlan
+		}
+		opts.dryRun = plan
+
+		return nil
+	}
+}
+
+// WithMaxResponseSize sets the limit of the decoded response body size in
+// bytes instead of the selvpcclient.DefaultMaxResponseSize. Response bodies
+// aren't limited if the size is negative.
+func WithMaxResponseSize(size int64) Option {
+	return func(opts *clientOptions) error {
+		if size == 0 {
+			return errZeroMaxResponseSize
+		}
+		opts.maxResponseSize = size
+
+		return nil
+	}
+}
+
+// WithGzip requests gzip compressed responses with the explicit
+// Accept-Encoding header. It's useful with custom transports that don't
+// request compression themselves.
+func WithGzip() Option {
+	return func(opts *clientOptions) error {
+		opts.acceptGzip = true
+
+		return nil
+	}
+}
+
+// WithKeepResponseBody keeps decoded response bodies in memory, so they
+// remain available through the ResponseResult's RawBody.
+func WithKeepResponseBody() Option {
+	return func(opts *clientOptions) error {
+		opts.keepResponseBody = true
+
+		return nil
+	}
+}
+
+// WithConfig sets the token, endpoint, timeout and retries from the provided
+// config. Empty endpoint and zero timeout and retries keep the defaults.
+// Options after the WithConfig override the config values, for example the
+// WithTokenSource replaces the config token.
+func WithConfig(cfg *selvpcclient.Config) Option {
+	return func(opts *clientOptions) error {
+		if cfg == nil {
+			return errNilConfig
+		}
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		configOpts := []Option{WithToken(cfg.Token)}
+		if cfg.Endpoint != "" {
+			configOpts = append(configOpts, WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Timeout > 0 {
+			configOpts = append(configOpts, WithTimeout(cfg.Timeout))
+		}
+		if retryPolicy := cfg.RetryPolicy(); retryPolicy != nil {
+			configOpts = append(configOpts, WithRetryPolicy(retryPolicy))
+		}
+		for _, opt := range configOpts {
+			if err := opt(opts); err != nil {
+				return err
+			}
+		}
+		opts.configToken = true
+
+		return nil
+	}
+}
+
+// validate checks the combination of the provided options.
+func (opts *clientOptions) validate() error {
+	if opts.tokenID == "" && opts.tokenSource == nil {
+		return errEmptyToken
+	}
+	if opts.tokenID != "" && opts.tokenSource != nil {
+		return errTokenWithTokenSource
+	}
+	if opts.debug && opts.logger == nil {
+		return errDebugWithoutLogger
+	}
+	if opts.httpClient != nil && opts.transport != nil {
+		return errHTTPClientWithTransport
+	}
+	if opts.httpClient != nil && (opts.requestTimeout || opts.transportTimeouts) {
+		return errHTTPClientWithTimeouts
+	}
+	if opts.transport != nil && opts.transportTimeouts {
+		return errTransportWithTimeouts
+	}
+
+	return nil
+}
+
+// buildHTTPClient returns the custom HTTP client or creates a new one with
+// the configured timeouts and transport.
+func (opts *clientOptions) buildHTTPClient() *http.Client {
+	if opts.httpClient != nil {
+		return opts.httpClient
+	}
+	httpClient := selvpcclient.NewHTTPClientWithTimeouts(opts.timeouts)
+	if opts.transport != nil {
+		httpClient.Transport = opts.transport
+	}
+
+	return httpClient
+}
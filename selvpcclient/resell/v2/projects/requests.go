@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -14,7 +13,10 @@ const resourceURL = "projects"
 
 // Get returns a single project by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*Project, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,7 +39,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*P
 
 // List gets a list of projects in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Project, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -46,10 +51,8 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Project,
 		return nil, responseResult, responseResult.Err
 	}
 
-	// Extract projects from the response body.
-	var result struct {
-		Projects []*Project `json:"projects"`
-	}
+	// Extract projects from the response body one by one.
+	var result projectsList
 	err = responseResult.ExtractResult(&result)
 	if err != nil {
 		return nil, responseResult, err
@@ -58,8 +61,40 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Project,
 	return result.Projects, responseResult, nil
 }
 
+// Iterator iterates over projects like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over all projects in the
+// current domain.
+func NewIterator(client *selvpcclient.ServiceClient) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(string) (string, error) {
+			return client.BuildURL(resourceURL)
+		}),
+		Key: "projects",
+		New: func() interface{} {
+			return &Project{}
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current project.
+func (iterator *Iterator) Value() *Project {
+	value, _ := iterator.ListIterator.Value().(*Project)
+
+	return value
+}
+
 // Create requests a creation of the project.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts CreateOpts) (*Project, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	// Nest create options into the parent "project" JSON structure.
 	type createProject struct {
 		Options CreateOpts `json:"project"`
@@ -70,7 +105,10 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -103,7 +141,10 @@ func Update(ctx context.Context, client *selvpcclient.ServiceClient, id string,
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPatch, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -126,7 +167,10 @@ func Update(ctx context.Context, client *selvpcclient.ServiceClient, id string,
 
 // Delete deletes a single project by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
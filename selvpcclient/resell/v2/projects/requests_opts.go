@@ -2,7 +2,9 @@ package projects
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
 )
 
@@ -51,6 +53,20 @@ func (opts *CreateOpts) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Validate checks that the project has a name and all provided quotas are
+// valid.
+func (opts *CreateOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.Name == "" {
+		validationErr.Add("name", "is required")
+	}
+	for i := range opts.Quotas {
+		validationErr.Merge(fmt.Sprintf("quotas[%d]", i), opts.Quotas[i].Validate())
+	}
+
+	return validationErr.Err()
+}
+
 // UpdateOpts represents options for the project Update request.
 type UpdateOpts struct {
 	// Name represents the name of a project.
@@ -3,6 +3,7 @@ package projects
 import (
 	"encoding/json"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
 )
 
@@ -78,6 +79,25 @@ func (result *Project) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// projectsList represents the response of the List request.
+type projectsList struct {
+	Projects []*Project `json:"projects"`
+}
+
+// UnmarshalJSONStream decodes projects from the decoder one by one, so only
+// a single project is buffered at once.
+func (result *projectsList) UnmarshalJSONStream(decoder *json.Decoder) error {
+	return selvpcclient.DecodeJSONArray(decoder, "projects", func() error {
+		project := &Project{}
+		if err := decoder.Decode(project); err != nil {
+			return err
+		}
+		result.Projects = append(result.Projects, project)
+
+		return nil
+	})
+}
+
 // Theme represents theme settings for a single project.
 type Theme struct {
 	// Color is a hex string with a custom background color.
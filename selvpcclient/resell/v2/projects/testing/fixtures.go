@@ -1,6 +1,9 @@
 package testing
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/projects"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
 )
@@ -358,3 +361,38 @@ const TestCreateProjectNoQuotasOptsRaw = `
 var TestCreateProjectNoQuotasOpts = projects.CreateOpts{
 	Name: "Project2",
 }
+
+// NewListProjectsResponseRaw returns a raw response from the List request with
+// the provided number of projects that have quotas of several resources.
+func NewListProjectsResponseRaw(projectsCount int) string {
+	resources := []string{"compute_cores", "compute_ram", "volume_gigabytes_fast", "network_floatingips"}
+	zones := []string{"ru-1a", "ru-2a", "ru-3a"}
+
+	var builder strings.Builder
+	builder.WriteString(`{"projects": [`)
+	for i := 0; i < projectsCount; i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		fmt.Fprintf(&builder, `{"id": "%032x", "name": "Project%d", "url": "https://%06d.selvpc.ru", `+
+			`"enabled": true, "custom_url": null, "theme": {"color": "", "logo": ""}, "quotas": {`, i, i, i)
+		for j, resource := range resources {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			fmt.Fprintf(&builder, `"%s": [`, resource)
+			for k, zone := range zones {
+				if k > 0 {
+					builder.WriteString(", ")
+				}
+				fmt.Fprintf(&builder, `{"region": "%s", "zone": "%s", "value": %d, "used": %d}`,
+					zone[:len(zone)-1], zone, 100*(k+1), 10*k)
+			}
+			builder.WriteString("]")
+		}
+		builder.WriteString("}}")
+	}
+	builder.WriteString("]}")
+
+	return builder.String()
+}
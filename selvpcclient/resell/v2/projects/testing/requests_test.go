@@ -2,11 +2,16 @@ package testing
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/projects"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
 
@@ -104,6 +109,15 @@ func TestGetProjectHTTPError(t *testing.T) {
 		t.Fatalf("expected %d status in the HTTP response, but got %d",
 			http.StatusBadGateway, httpResponse.StatusCode)
 	}
+
+	var apiErr *selvpcclient.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *selvpcclient.APIError from the Get method, but got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected %d status in the API error, but got %d",
+			http.StatusBadGateway, apiErr.StatusCode)
+	}
 }
 
 func TestGetProjectTimeoutError(t *testing.T) {
@@ -657,3 +671,132 @@ func TestDeleteProjectTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestCreateOptsValidateQuotas(t *testing.T) {
+	value := -1
+	createOpts := projects.CreateOpts{
+		Quotas: []quotas.QuotaOpts{
+			{
+				Name: "compute_cores",
+				ResourceQuotasOpts: []quotas.ResourceQuotaOpts{
+					{Value: &value},
+				},
+			},
+		},
+	}
+
+	var validationErr *selvpcclient.ValidationError
+	if !errors.As(createOpts.Validate(), &validationErr) {
+		t.Fatalf("expected ValidationError, but got %v", createOpts.Validate())
+	}
+	expected := "selvpcclient: invalid options: name is required; quotas[0].values[0].value can't be negative"
+	if validationErr.Error() != expected {
+		t.Fatalf("expected %q, but got %q", expected, validationErr.Error())
+	}
+}
+
+func TestProjectsIterator(t *testing.T) {
+	endpointCalled := false
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testutils.HandleReqWithoutBody(t, &testutils.HandleReqOpts{
+		Mux:         testEnv.Mux,
+		URL:         "/resell/v2/projects",
+		RawResponse: TestListProjectsResponseRaw,
+		Method:      http.MethodGet,
+		Status:      http.StatusOK,
+		CallFlag:    &endpointCalled,
+	})
+
+	ctx := context.Background()
+	expected, _, err := projects.List(ctx, testEnv.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []*projects.Project
+	iterator := projects.NewIterator(testEnv.Client)
+	for iterator.Next(ctx) {
+		actual = append(actual, iterator.Value())
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !endpointCalled {
+		t.Fatal("endpoint wasn't called")
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v, but got %#v", expected, actual)
+	}
+}
+
+// benchmarkSink keeps results of the benchmarks alive.
+var benchmarkSink interface{}
+
+func BenchmarkListProjects(b *testing.B) {
+	body := []byte(NewListProjectsResponseRaw(2000))
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Mux.HandleFunc("/resell/v2/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(body)
+	})
+	ctx := context.Background()
+	url := testEnv.Server.URL + "/resell/v2/projects"
+
+	// readAll reads and decodes the whole body like it was done before the
+	// streaming decoding.
+	readAll := func() {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		request.Header.Set("X-Token", testEnv.Client.TokenID)
+		response, err := testEnv.Client.HTTPClient.Do(request)
+		if err != nil {
+			b.Fatal(err)
+		}
+		rawBody, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		var result struct {
+			Projects []*projects.Project `json:"projects"`
+		}
+		if err := json.Unmarshal(rawBody, &result); err != nil {
+			b.Fatal(err)
+		}
+		benchmarkSink = result.Projects
+	}
+	stream := func() {
+		allProjects, _, err := projects.List(ctx, testEnv.Client)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchmarkSink = allProjects
+	}
+
+	for _, benchmark := range []struct {
+		name string
+		run  func()
+	}{
+		{name: "read all", run: readAll},
+		{name: "stream", run: stream},
+	} {
+		benchmark := benchmark
+		b.Run(benchmark.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				benchmark.run()
+			}
+			testutils.ReportPeakHeap(b, benchmark.run)
+			benchmarkSink = nil
+		})
+	}
+}
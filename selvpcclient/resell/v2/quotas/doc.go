@@ -35,6 +35,10 @@ Example of getting projects quotas for a domain
     }
   }
 
+Projects quotas are decoded from the response body while it's read, so large
+domains don't need the whole response in memory. Projects and their quotas are
+returned in the response order.
+
 Example of getting quotas for a single project
 
   singleProjectQuotas, _, err := quotas.GetProjectQuotas(ctx, resellClient, updateProjectID)
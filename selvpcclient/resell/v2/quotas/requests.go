@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -14,7 +13,10 @@ const resourceURL = "quotas"
 
 // GetAll returns the total amount of resources available to be allocated to projects.
 func GetAll(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Quota, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -35,7 +37,10 @@ func GetAll(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Quota,
 
 // GetFree returns the current amount of resources available to be allocated to projects.
 func GetFree(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Quota, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "free"}, "/")
+	url, err := client.BuildURL(resourceURL, "free")
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -56,7 +61,10 @@ func GetFree(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Quota,
 
 // GetProjectsQuotas returns the quotas info for all domain projects.
 func GetProjectsQuotas(ctx context.Context, client *selvpcclient.ServiceClient) ([]*ProjectQuota, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects"}, "/")
+	url, err := client.BuildURL(resourceURL, "projects")
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -77,7 +85,10 @@ func GetProjectsQuotas(ctx context.Context, client *selvpcclient.ServiceClient)
 
 // GetProjectQuotas returns the quotas info for a single project referenced by id.
 func GetProjectQuotas(ctx context.Context, client *selvpcclient.ServiceClient, id string) ([]*Quota, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", id}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -98,12 +109,19 @@ func GetProjectQuotas(ctx context.Context, client *selvpcclient.ServiceClient, i
 
 // UpdateProjectQuotas updates the quotas info for a single project referenced by id.
 func UpdateProjectQuotas(ctx context.Context, client *selvpcclient.ServiceClient, id string, updateOpts UpdateProjectQuotasOpts) ([]*Quota, *selvpcclient.ResponseResult, error) {
+	if err := updateOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	requestBody, err := json.Marshal(&updateOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", id}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPatch, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -3,6 +3,9 @@ package quotas
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
 
 var errGetEmptyQuotasOpts = errors.New("got empty QuotasOpts")
@@ -72,3 +75,40 @@ func (opts *UpdateProjectQuotasOpts) MarshalJSON() ([]byte, error) {
 		ResourceQuotasOpts: resourceQuotasMap,
 	})
 }
+
+// Validate checks that at least one resource quota is provided and every
+// quota is valid.
+func (opts *UpdateProjectQuotasOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.QuotasOpts) == 0 {
+		validationErr.Add("quotas", "must contain at least one resource quota")
+	}
+	for i := range opts.QuotasOpts {
+		validationErr.Merge(fmt.Sprintf("quotas[%d]", i), opts.QuotasOpts[i].Validate())
+	}
+
+	return validationErr.Err()
+}
+
+// Validate checks that the quota has a resource name and every location has a
+// non-negative value.
+func (opts *QuotaOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.Name == "" {
+		validationErr.Add("name", "is required")
+	}
+	if len(opts.ResourceQuotasOpts) == 0 {
+		validationErr.Add("values", "must contain at least one quota value")
+	}
+	for i, resourceQuota := range opts.ResourceQuotasOpts {
+		field := fmt.Sprintf("values[%d].value", i)
+		switch {
+		case resourceQuota.Value == nil:
+			validationErr.Add(field, "is required")
+		case *resourceQuota.Value < 0:
+			validationErr.Add(field, "can't be negative")
+		}
+	}
+
+	return validationErr.Err()
+}
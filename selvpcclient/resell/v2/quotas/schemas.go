@@ -1,7 +1,9 @@
 package quotas
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 )
 
 // Quota represents a quota information for a single billing resource.
@@ -53,36 +55,33 @@ We need it to work with a JSON structure that the Resell v2 API responses with:
     }
 */
 func (result *ResourcesQuotas) UnmarshalJSON(b []byte) error {
-	// Populate temporary structure with resource quotas represented as maps.
-	var s struct {
-		ResourcesQuotas map[string][]ResourceQuotaEntity `json:"quotas"`
-	}
-	err := json.Unmarshal(b, &s)
+	return result.UnmarshalJSONStream(json.NewDecoder(bytes.NewReader(b)))
+}
+
+// UnmarshalJSONStream decodes the ResourcesQuotas from the decoder token by
+// token, so resource quotas are added in the response order without
+// intermediate maps.
+func (result *ResourcesQuotas) UnmarshalJSONStream(decoder *json.Decoder) error {
+	var resourcesQuotas []Quota
+	err := decodeObject(decoder, func(key string) error {
+		if key != "quotas" {
+			return skipValue(decoder)
+		}
+		var err error
+		resourcesQuotas, err = decodeQuotas(decoder, resourcesQuotas)
+
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	// Populate the result with an empty slice in case of empty quota list.
 	*result = ResourcesQuotas{
-		Quotas: []*Quota{},
+		Quotas: make([]*Quota, len(resourcesQuotas)),
 	}
-
-	if len(s.ResourcesQuotas) != 0 {
-		// Convert resource quota maps to the slice of Quota types.
-		// Here we're allocating memory in advance because we already know the length
-		// of a result slice from the JSON bytearray.
-		resourceQuotasSlice := make([]*Quota, len(s.ResourcesQuotas))
-		i := 0
-		for resourceName, resourceQuotas := range s.ResourcesQuotas {
-			resourceQuotasSlice[i] = &Quota{
-				Name:                   resourceName,
-				ResourceQuotasEntities: resourceQuotas,
-			}
-			i++
-		}
-
-		// Add the unmarshalled quotas slice to the result.
-		result.Quotas = resourceQuotasSlice
+	for i := range resourcesQuotas {
+		result.Quotas[i] = &resourcesQuotas[i]
 	}
 
 	return nil
@@ -124,46 +123,112 @@ We need it to work with a JSON structure that the Resell v2 API responses with:
     }
 */
 func (result *ProjectsQuotas) UnmarshalJSON(b []byte) error {
-	// Populate temporary structure with projects quotas represented as maps.
-	var s struct {
-		ProjectsQuotas map[string]map[string][]ResourceQuotaEntity `json:"quotas"`
-	}
-	err := json.Unmarshal(b, &s)
+	return result.UnmarshalJSONStream(json.NewDecoder(bytes.NewReader(b)))
+}
+
+// UnmarshalJSONStream decodes the ProjectsQuotas from the decoder token by
+// token, so only quotas of a single resource are buffered at once and
+// projects are added in the response order.
+func (result *ProjectsQuotas) UnmarshalJSONStream(decoder *json.Decoder) error {
+	// Populate the result with an empty slice in case of empty quota list.
+	projectsQuotas := []*ProjectQuota{}
+	err := decodeObject(decoder, func(key string) error {
+		if key != "quotas" {
+			return skipValue(decoder)
+		}
+		return decodeObject(decoder, func(projectID string) error {
+			// Projects usually have the same number of resources, so the
+			// previous one is used to allocate quotas in advance.
+			var capacity int
+			if len(projectsQuotas) > 0 {
+				capacity = len(projectsQuotas[len(projectsQuotas)-1].ProjectQuotas)
+			}
+			projectQuotas, err := decodeQuotas(decoder, make([]Quota, 0, capacity))
+			if err != nil {
+				return err
+			}
+			if len(projectQuotas) == 0 {
+				projectQuotas = nil
+			}
+			projectsQuotas = append(projectsQuotas, &ProjectQuota{
+				ID:            projectID,
+				ProjectQuotas: projectQuotas,
+			})
+
+			return nil
+		})
+	})
 	if err != nil {
 		return err
 	}
-
-	// Populate the result with an empty slice in case of empty quota list.
 	*result = ProjectsQuotas{
-		ProjectQuotas: []*ProjectQuota{},
+		ProjectQuotas: projectsQuotas,
 	}
 
-	if len(s.ProjectsQuotas) != 0 {
-		// Convert projects quota maps to the slice of ProjectQuota types.
-		// Here we're allocating memory in advance for both of project and resource quotas
-		// because we already know the length of each slices from the JSON bytearray.
-		projectQuotasSlice := make([]*ProjectQuota, len(s.ProjectsQuotas))
-		i := 0
-		for projectName, projectQuotas := range s.ProjectsQuotas {
-			resourceQuotasSlice := make([]Quota, len(projectQuotas))
-			j := 0
-			for resourceName, resourceQuotas := range projectQuotas {
-				resourceQuotasSlice[j] = Quota{
-					Name:                   resourceName,
-					ResourceQuotasEntities: resourceQuotas,
-				}
-				j++
-			}
-			projectQuotasSlice[i] = &ProjectQuota{
-				ID:            projectName,
-				ProjectQuotas: resourceQuotasSlice,
-			}
-			i++
+	return nil
+}
+
+// decodeQuotas decodes the JSON object with quotas of different resources
+// from the decoder and appends them to the provided slice in the response
+// order. Quotas are decoded in place to avoid allocations of every single
+// quota.
+func decodeQuotas(decoder *json.Decoder, resourcesQuotas []Quota) ([]Quota, error) {
+	err := decodeObject(decoder, func(resourceName string) error {
+		// Resources usually have quotas in the same locations, so the previous
+		// one is used to allocate entities in advance.
+		var capacity int
+		if len(resourcesQuotas) > 0 {
+			capacity = len(resourcesQuotas[len(resourcesQuotas)-1].ResourceQuotasEntities)
 		}
+		resourcesQuotas = append(resourcesQuotas, Quota{
+			Name:                   resourceName,
+			ResourceQuotasEntities: make([]ResourceQuotaEntity, 0, capacity),
+		})
 
-		// Add the unmarshalled project quotas slice to the result.
-		result.ProjectQuotas = projectQuotasSlice
+		return decoder.Decode(&resourcesQuotas[len(resourcesQuotas)-1].ResourceQuotasEntities)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return resourcesQuotas, nil
+}
+
+// decodeObject reads the JSON object from the decoder and calls the provided
+// function for every key while the decoder is positioned before its value.
+// Nothing is called for the null value.
+func decodeObject(decoder *json.Decoder, decodeValue func(key string) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("quotas: expected a JSON object, but got %v", token)
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		if err := decodeValue(key); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing brace of the object.
+	_, err = decoder.Token()
+
+	return err
+}
+
+// skipValue reads the next JSON value from the decoder and discards it.
+func skipValue(decoder *json.Decoder) error {
+	var skipped json.RawMessage
+
+	return decoder.Decode(&skipped)
 }
@@ -1,6 +1,11 @@
 package testing
 
-import "github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
+)
 
 // TestGetAllQuotasResponseRaw represents a raw response from the GetAll request.
 const TestGetAllQuotasResponseRaw = `
@@ -400,3 +405,61 @@ const TestQuotasInvalidResponseRaw = `
 var TestUpdateQuotasInvalidOpts = quotas.UpdateProjectQuotasOpts{
 	QuotasOpts: []quotas.QuotaOpts{},
 }
+
+// benchmarkResources contains names of resources of the generated quotas.
+var benchmarkResources = []string{
+	"compute_cores",
+	"compute_ram",
+	"volume_gigabytes_fast",
+	"volume_gigabytes_basic",
+	"volume_gigabytes_universal",
+	"image_gigabytes",
+	"network_floatingips",
+	"network_subnets_29",
+	"network_subnets_29_vrrp",
+	"load_balancers",
+}
+
+// benchmarkZones contains zones of the generated quotas.
+var benchmarkZones = []string{"ru-1a", "ru-2a", "ru-3a"}
+
+// NewProjectsQuotasResponseRaw returns a raw response from the GetProjectsQuotas
+// request with quotas of the provided number of projects.
+func NewProjectsQuotasResponseRaw(projectsCount int) string {
+	var builder strings.Builder
+	builder.WriteString(`{"quotas": {`)
+	for i := 0; i < projectsCount; i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		fmt.Fprintf(&builder, `"%032x": `, i)
+		builder.WriteString(NewResourcesQuotasRaw())
+	}
+	builder.WriteString(`}}`)
+
+	return builder.String()
+}
+
+// NewResourcesQuotasRaw returns a raw JSON object with quotas of resources in
+// different zones like in the responses of the Resell API.
+func NewResourcesQuotasRaw() string {
+	var builder strings.Builder
+	builder.WriteString("{")
+	for i, resource := range benchmarkResources {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		fmt.Fprintf(&builder, `"%s": [`, resource)
+		for j, zone := range benchmarkZones {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			fmt.Fprintf(&builder, `{"region": "%s", "zone": "%s", "value": %d, "used": %d}`,
+				zone[:len(zone)-1], zone, 100*(j+1), 10*j)
+		}
+		builder.WriteString("]")
+	}
+	builder.WriteString("}")
+
+	return builder.String()
+}
@@ -2,6 +2,9 @@ package testing
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
@@ -353,6 +356,50 @@ func TestGetProjectsQuotas(t *testing.T) {
 	}
 }
 
+func TestGetProjectsQuotasOrder(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Client.KeepResponseBody = true
+	testEnv.Mux.HandleFunc("/resell/v2/quotas/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Write([]byte(NewProjectsQuotasResponseRaw(3)))
+	})
+
+	ctx := context.Background()
+	actual, responseResult, err := quotas.GetProjectsQuotas(ctx, testEnv.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawBody, err := responseResult.RawBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rawBody) != NewProjectsQuotasResponseRaw(3) {
+		t.Fatalf("expected the raw body to be preserved, but got %s", rawBody)
+	}
+
+	if len(actual) != 3 {
+		t.Fatalf("expected 3 projects, but got %d", len(actual))
+	}
+	for i, project := range actual {
+		if expectedID := fmt.Sprintf("%032x", i); project.ID != expectedID {
+			t.Errorf("expected %s project at position %d, but got %s", expectedID, i, project.ID)
+		}
+		if len(project.ProjectQuotas) != len(benchmarkResources) {
+			t.Fatalf("expected %d project quotas, but got %d", len(benchmarkResources), len(project.ProjectQuotas))
+		}
+		for j, quota := range project.ProjectQuotas {
+			if quota.Name != benchmarkResources[j] {
+				t.Errorf("expected %s quota at position %d, but got %s", benchmarkResources[j], j, quota.Name)
+			}
+			if len(quota.ResourceQuotasEntities) != len(benchmarkZones) {
+				t.Errorf("expected %d quota entities, but got %d", len(benchmarkZones), len(quota.ResourceQuotasEntities))
+			}
+		}
+	}
+}
+
 func TestGetProjectsQuotasSingle(t *testing.T) {
 	endpointCalled := false
 
@@ -778,3 +825,83 @@ func TestUpdateProjectQuotasMarshallError(t *testing.T) {
 		t.Fatal("expected error from the Update method")
 	}
 }
+
+// benchmarkSink keeps results of the benchmarks alive.
+var benchmarkSink interface{}
+
+func BenchmarkGetProjectsQuotas(b *testing.B) {
+	body := []byte(NewProjectsQuotasResponseRaw(2000))
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Mux.HandleFunc("/resell/v2/quotas/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(body)
+	})
+	ctx := context.Background()
+	url := testEnv.Server.URL + "/resell/v2/quotas/projects"
+
+	// readAll reads and decodes the whole body like it was done before the
+	// streaming decoding: the body is read with the ioutil.ReadAll, decoded
+	// into maps and converted to slices.
+	readAll := func() {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		request.Header.Set("X-Token", testEnv.Client.TokenID)
+		response, err := testEnv.Client.HTTPClient.Do(request)
+		if err != nil {
+			b.Fatal(err)
+		}
+		rawBody, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		var result struct {
+			ProjectsQuotas map[string]map[string][]quotas.ResourceQuotaEntity `json:"quotas"`
+		}
+		if err := json.Unmarshal(rawBody, &result); err != nil {
+			b.Fatal(err)
+		}
+		projectsQuotas := make([]*quotas.ProjectQuota, 0, len(result.ProjectsQuotas))
+		for id, resourcesQuotas := range result.ProjectsQuotas {
+			projectQuota := &quotas.ProjectQuota{ID: id}
+			for name, entities := range resourcesQuotas {
+				projectQuota.ProjectQuotas = append(projectQuota.ProjectQuotas, quotas.Quota{
+					Name:                   name,
+					ResourceQuotasEntities: entities,
+				})
+			}
+			projectsQuotas = append(projectsQuotas, projectQuota)
+		}
+		benchmarkSink = projectsQuotas
+	}
+	stream := func() {
+		projectsQuotas, _, err := quotas.GetProjectsQuotas(ctx, testEnv.Client)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchmarkSink = projectsQuotas
+	}
+
+	for _, benchmark := range []struct {
+		name string
+		run  func()
+	}{
+		{name: "read all", run: readAll},
+		{name: "stream", run: stream},
+	} {
+		benchmark := benchmark
+		b.Run(benchmark.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				benchmark.run()
+			}
+			testutils.ReportPeakHeap(b, benchmark.run)
+			benchmarkSink = nil
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -14,7 +13,10 @@ const resourceURL = "roles"
 
 // List returns all roles in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Role, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -35,9 +37,40 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*Role, *se
 	return result.Roles, responseResult, nil
 }
 
+// Iterator iterates over roles like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over all roles in the current
+// domain.
+func NewIterator(client *selvpcclient.ServiceClient) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(string) (string, error) {
+			return client.BuildURL(resourceURL)
+		}),
+		Key: "roles",
+		New: func() interface{} {
+			return &Role{}
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current role.
+func (iterator *Iterator) Value() *Role {
+	value, _ := iterator.ListIterator.Value().(*Role)
+
+	return value
+}
+
 // ListProject returns all roles in the specified project.
 func ListProject(ctx context.Context, client *selvpcclient.ServiceClient, id string) ([]*Role, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", id}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -60,7 +93,10 @@ func ListProject(ctx context.Context, client *selvpcclient.ServiceClient, id str
 
 // ListUser returns all roles that are associated with the specified user.
 func ListUser(ctx context.Context, client *selvpcclient.ServiceClient, id string) ([]*Role, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "users", id}, "/")
+	url, err := client.BuildURL(resourceURL, "users", id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -83,7 +119,14 @@ func ListUser(ctx context.Context, client *selvpcclient.ServiceClient, id string
 
 // Create requests a creation of the single role for the specified project and user.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts RoleOpt) (*Role, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", createOpts.ProjectID, "users", createOpts.UserID}, "/")
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	url, err := client.BuildURL(resourceURL, "projects", createOpts.ProjectID, "users", createOpts.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -106,13 +149,20 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 
 // CreateBulk requests a creation of several roles.
 func CreateBulk(ctx context.Context, client *selvpcclient.ServiceClient, createOpts RoleOpts) ([]*Role, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	createRolesOpts := &createOpts
 	requestBody, err := json.Marshal(createRolesOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -135,7 +185,14 @@ func CreateBulk(ctx context.Context, client *selvpcclient.ServiceClient, createO
 
 // Delete requests a deletion of the single role for the specified project and user.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, deleteOpts RoleOpt) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", deleteOpts.ProjectID, "users", deleteOpts.UserID}, "/")
+	if err := deleteOpts.Validate(); err != nil {
+		return nil, err
+	}
+
+	url, err := client.BuildURL(resourceURL, "projects", deleteOpts.ProjectID, "users", deleteOpts.UserID)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
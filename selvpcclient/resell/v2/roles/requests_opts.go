@@ -1,5 +1,11 @@
 package roles
 
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
 // RoleOpts represents options for several Resell roles.
 type RoleOpts struct {
 	Roles []RoleOpt `json:"roles"`
@@ -13,3 +19,29 @@ type RoleOpt struct {
 	// UserID represents Identity service user.
 	UserID string `json:"user_id"`
 }
+
+// Validate checks that every role has a project and a user.
+func (opts *RoleOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.Roles) == 0 {
+		validationErr.Add("roles", "must contain at least one role")
+	}
+	for i := range opts.Roles {
+		validationErr.Merge(fmt.Sprintf("roles[%d]", i), opts.Roles[i].Validate())
+	}
+
+	return validationErr.Err()
+}
+
+// Validate checks that the role has a project and a user.
+func (opts *RoleOpt) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.ProjectID == "" {
+		validationErr.Add("project_id", "is required")
+	}
+	if opts.UserID == "" {
+		validationErr.Add("user_id", "is required")
+	}
+
+	return validationErr.Err()
+}
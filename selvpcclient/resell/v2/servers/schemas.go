@@ -14,9 +14,76 @@ type Server struct {
 	Name string `json:"name"`
 
 	// Status represents a current status of the server.
-	Status string `json:"status"`
+	Status Status `json:"status"`
 
 	// Updated contains the ISO-8601 timestamp of when the state of the server
 	// last changed.
 	Updated time.Time `json:"updated"`
 }
+
+// Status represents a status of the server.
+type Status string
+
+const (
+	// StatusActive represents the running server.
+	StatusActive Status = "ACTIVE"
+
+	// StatusBuild represents the server that is being created.
+	StatusBuild Status = "BUILD"
+
+	// StatusShutoff represents the stopped server.
+	StatusShutoff Status = "SHUTOFF"
+
+	// StatusPaused represents the paused server.
+	StatusPaused Status = "PAUSED"
+
+	// StatusSuspended represents the suspended server.
+	StatusSuspended Status = "SUSPENDED"
+
+	// StatusRescue represents the server in the rescue mode.
+	StatusRescue Status = "RESCUE"
+
+	// StatusReboot represents the server that is being rebooted.
+	StatusReboot Status = "REBOOT"
+
+	// StatusHardReboot represents the server that is being hard rebooted.
+	StatusHardReboot Status = "HARD_REBOOT"
+
+	// StatusResize represents the server that is being resized.
+	StatusResize Status = "RESIZE"
+
+	// StatusVerifyResize represents the resized server that waits for a confirmation.
+	StatusVerifyResize Status = "VERIFY_RESIZE"
+
+	// StatusShelved represents the shelved server.
+	StatusShelved Status = "SHELVED"
+
+	// StatusShelvedOffloaded represents the shelved server that is removed from the hypervisor.
+	StatusShelvedOffloaded Status = "SHELVED_OFFLOADED"
+
+	// StatusError represents the server that failed.
+	StatusError Status = "ERROR"
+)
+
+// IsKnown checks if the status is one of the statuses that are known to the
+// client. Unknown statuses returned by the API are kept as is.
+func (s Status) IsKnown() bool {
+	switch s {
+	case StatusActive,
+		StatusBuild,
+		StatusShutoff,
+		StatusPaused,
+		StatusSuspended,
+		StatusRescue,
+		StatusReboot,
+		StatusHardReboot,
+		StatusResize,
+		StatusVerifyResize,
+		StatusShelved,
+		StatusShelvedOffloaded,
+		StatusError:
+		return true
+	}
+
+	return false
+}
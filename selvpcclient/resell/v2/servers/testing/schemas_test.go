@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/servers"
+)
+
+func TestServerStatusIsKnown(t *testing.T) {
+	statuses := []servers.Status{
+		servers.StatusActive,
+		servers.StatusBuild,
+		servers.StatusShutoff,
+		servers.StatusPaused,
+		servers.StatusSuspended,
+		servers.StatusRescue,
+		servers.StatusReboot,
+		servers.StatusHardReboot,
+		servers.StatusResize,
+		servers.StatusVerifyResize,
+		servers.StatusShelved,
+		servers.StatusShelvedOffloaded,
+		servers.StatusError,
+	}
+	for _, status := range statuses {
+		if !status.IsKnown() {
+			t.Errorf("expected %s status to be known", status)
+		}
+	}
+	if servers.Status("MIGRATING").IsKnown() {
+		t.Error("expected MIGRATING status to be unknown")
+	}
+}
+
+func TestServerUnknownStatus(t *testing.T) {
+	var server servers.Server
+	if err := json.Unmarshal([]byte(`{"id": "server-id", "status": "MIGRATING"}`), &server); err != nil {
+		t.Fatal(err)
+	}
+	if server.Status != "MIGRATING" {
+		t.Fatalf("expected the unknown status to be kept, but got %q", server.Status)
+	}
+}
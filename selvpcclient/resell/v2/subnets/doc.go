@@ -46,5 +46,12 @@ Example of deleting a single subnet
   if err != nil {
     log.Fatal(err)
   }
+
+Example of waiting until a subnet is deleted
+
+  err = subnets.WaitForDeleted(ctx, resellClient, subnetID, selvpcclient.WaitOpts{Timeout: 5 * time.Minute})
+  if err != nil {
+    log.Fatal(err)
+  }
 */
 package subnets
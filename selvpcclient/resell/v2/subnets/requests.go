@@ -5,16 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
 
 const resourceURL = "subnets"
 
+// failedStatuses contains terminal statuses of the failed resources.
+var failedStatuses = []string{string(StatusError)}
+
 // Get returns a single subnet by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*Subnet, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,15 +43,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*S
 
 // List gets a list of subnets in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts) ([]*Subnet, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
-
-	queryParams, err := selvpcclient.BuildQueryParameters(opts)
+	url, err := client.BuildURLWithQuery(opts, resourceURL)
 	if err != nil {
 		return nil, nil, err
 	}
-	if queryParams != "" {
-		url = strings.Join([]string{url, queryParams}, "?")
-	}
 
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -64,18 +65,73 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts
 		return nil, responseResult, err
 	}
 
-	return result.Subnets, responseResult, nil
+	filtered := make([]*Subnet, 0, len(result.Subnets))
+	for _, subnet := range result.Subnets {
+		if opts.matches(subnet) {
+			filtered = append(filtered, subnet)
+		}
+	}
+
+	return filtered, responseResult, nil
+}
+
+// Iterator iterates over subnets like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over subnets that match the
+// options. Pages of the opts.Limit size are requested if the limit is set.
+func NewIterator(client *selvpcclient.ServiceClient, opts ListOpts) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(marker string) (string, error) {
+			pageOpts := opts
+			if marker != "" {
+				pageOpts.Marker = marker
+			}
+			return client.BuildURLWithQuery(pageOpts, resourceURL)
+		}),
+		Key: "subnets",
+		New: func() interface{} {
+			return &Subnet{}
+		},
+		Marker: func(value interface{}) string {
+			return strconv.Itoa(value.(*Subnet).ID)
+		},
+		Limit: opts.Limit,
+		Filter: func(value interface{}) bool {
+			return opts.matches(value.(*Subnet))
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current subnet.
+func (iterator *Iterator) Value() *Subnet {
+	value, _ := iterator.ListIterator.Value().(*Subnet)
+
+	return value
 }
 
 // Create requests a creation of the subnets in the specified project.
+// The Idempotency-Key header is sent if the context contains a key that is
+// set by the selvpcclient.WithIdempotencyKey.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts SubnetOpts) ([]*Subnet, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	createSubnetsOpts := &createOpts
 	requestBody, err := json.Marshal(createSubnetsOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", projectID}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", projectID)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -96,9 +152,57 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID s
 	return result.Subnets, responseResult, nil
 }
 
+// CreateWithReconciliation requests a creation of the subnets like the Create
+// with the selvpcclient.CreateWithReconciliation, subnets are matched by their
+// regions and IP versions. Pass subnets of the project from a previous List as
+// the existing ones to skip the List before the creation.
+func CreateWithReconciliation(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts SubnetOpts, existing []*Subnet) ([]*Subnet, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	requested := make(map[string]int, len(createOpts.Subnets))
+	for _, opt := range createOpts.Subnets {
+		requested[opt.Region+"/"+string(opt.Type)] += opt.Quantity
+	}
+	var createdSubnets []*Subnet
+	reconciled, responseResult, err := selvpcclient.CreateWithReconciliation(ctx, selvpcclient.ReconcileOpts{
+		List: func(ctx context.Context) ([]selvpcclient.ReconcileItem, error) {
+			subnets, _, err := List(ctx, client, ListOpts{ProjectID: projectID})
+			if err != nil {
+				return nil, err
+			}
+			return reconcileItems(projectID, subnets), nil
+		},
+		Create: func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+			var (
+				responseResult *selvpcclient.ResponseResult
+				err            error
+			)
+			createdSubnets, responseResult, err = Create(ctx, client, projectID, createOpts)
+			return responseResult, err
+		},
+		Requested: requested,
+		Existing:  reconcileItems(projectID, existing),
+	})
+	if reconciled == nil {
+		return createdSubnets, responseResult, err
+	}
+
+	reconciledSubnets := make([]*Subnet, 0, len(reconciled))
+	for _, value := range reconciled {
+		reconciledSubnets = append(reconciledSubnets, value.(*Subnet))
+	}
+
+	return reconciledSubnets, responseResult, nil
+}
+
 // Delete deletes a single subnet by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
@@ -108,3 +212,58 @@ func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string)
 	}
 	return responseResult, err
 }
+
+// WaitForStatus polls the subnet until it reaches one of the provided
+// statuses and returns its last state. The selvpcclient.UnexpectedStatusError
+// is returned if the subnet reaches the ERROR status or is deleted.
+func WaitForStatus(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts, statuses ...Status) (*Subnet, error) {
+	target := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		target = append(target, string(status))
+	}
+	value, err := selvpcclient.WaitForResource(ctx, opts, resourceURL, id, getFunc(client, id), target, failedStatuses)
+	subnet, _ := value.(*Subnet)
+
+	return subnet, err
+}
+
+// WaitForDeleted polls the subnet until it doesn't exist anymore.
+func WaitForDeleted(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts) error {
+	_, err := WaitForStatus(ctx, client, id, opts, Status(selvpcclient.StatusDeleted))
+
+	return err
+}
+
+// getFunc returns the function that gets the subnet for the
+// selvpcclient.WaitForResource.
+func getFunc(client *selvpcclient.ServiceClient, id string) selvpcclient.ResourceGetFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		subnet, _, err := Get(ctx, client, id)
+		if err != nil || subnet == nil {
+			return nil, "", err
+		}
+
+		return subnet, string(subnet.Status), nil
+	}
+}
+
+// reconcileItems converts subnets of the project for the
+// selvpcclient.CreateWithReconciliation. It returns nil for nil subnets.
+func reconcileItems(projectID string, subnets []*Subnet) []selvpcclient.ReconcileItem {
+	if subnets == nil {
+		return nil
+	}
+	items := make([]selvpcclient.ReconcileItem, 0, len(subnets))
+	for _, subnet := range subnets {
+		if subnet.ProjectID != projectID {
+			continue
+		}
+		items = append(items, selvpcclient.ReconcileItem{
+			ID:    strconv.Itoa(subnet.ID),
+			Key:   subnet.Region + "/" + string(subnet.IPVersion()),
+			Value: subnet,
+		})
+	}
+
+	return items
+}
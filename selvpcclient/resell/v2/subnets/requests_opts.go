@@ -1,6 +1,10 @@
 package subnets
 
-import "github.com/selectel/go-selvpcclient/selvpcclient"
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
 
 // SubnetOpts represents options for the subnets Create request.
 type SubnetOpts struct {
@@ -23,7 +27,68 @@ type SubnetOpt struct {
 	PrefixLength int `json:"prefix_length"`
 }
 
-// ListOpts represents options for the licenses List request.
+// ListOpts represents options for the subnets List request.
+// Filters are sent as query parameters and are also applied to the
+// response in case the API ignores some of them.
 type ListOpts struct {
+	// Detailed requests additional details like associated servers.
 	Detailed bool `param:"detailed"`
+
+	// Limit sets the maximum number of subnets in the response.
+	// It's used as the page size by the Iterator.
+	Limit int `param:"limit"`
+
+	// Marker sets the ID of the last subnet of the previous page.
+	Marker string `param:"marker"`
+
+	// ProjectID filters subnets by the project.
+	ProjectID string `param:"project_id"`
+
+	// Region filters subnets by the region.
+	Region string `param:"region"`
+
+	// Status filters subnets by the status.
+	Status Status `param:"status"`
+
+	// IPVersion filters subnets by the IP version.
+	IPVersion selvpcclient.IPVersion `param:"type"`
+}
+
+// Validate checks that every subnet has a region, a positive quantity, a known
+// IP version and a prefix length that is valid for it.
+func (opts *SubnetOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.Subnets) == 0 {
+		validationErr.Add("subnets", "must contain at least one subnet")
+	}
+	for i, opt := range opts.Subnets {
+		field := fmt.Sprintf("subnets[%d]", i)
+		if opt.Region == "" {
+			validationErr.Add(field+".region", "is required")
+		}
+		if opt.Quantity < 1 {
+			validationErr.Add(field+".quantity", "must be positive")
+		}
+		if !opt.Type.IsValid() {
+			validationErr.Addf(field+".type", "must be %s or %s", selvpcclient.IPv4, selvpcclient.IPv6)
+		} else if err := selvpcclient.ValidatePrefixLength(opt.Type, opt.PrefixLength); err != nil {
+			validationErr.Add(field+".prefix_length", err.Error())
+		}
+	}
+
+	return validationErr.Err()
+}
+
+// matches checks if the subnet satisfies all set filters. Subnets with an
+// unknown IP version are left to the server-side filter.
+func (opts ListOpts) matches(subnet *Subnet) bool {
+	switch {
+	case opts.ProjectID != "" && opts.ProjectID != subnet.ProjectID,
+		opts.Region != "" && opts.Region != subnet.Region,
+		opts.Status != "" && opts.Status != subnet.Status,
+		opts.IPVersion != "" && subnet.IPVersion() != "" && opts.IPVersion != subnet.IPVersion():
+		return false
+	}
+
+	return true
 }
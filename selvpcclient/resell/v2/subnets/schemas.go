@@ -1,6 +1,9 @@
 package subnets
 
-import "github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/servers"
+import (
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/servers"
+)
 
 // Subnet represents a single Resell subnet.
 type Subnet struct {
@@ -8,7 +11,7 @@ type Subnet struct {
 	ID int `json:"id"`
 
 	// Status shows if subnet is used.
-	Status string `json:"status"`
+	Status Status `json:"status"`
 
 	// Servers contains info about servers to which subnet is associated to.
 	Servers []servers.Server `json:"servers"`
@@ -34,3 +37,34 @@ type Subnet struct {
 	// VTEPIPAddress represents an ip address of the associated VTEP in the Networking service.
 	VTEPIPAddress string `json:"vtep_ip_address"`
 }
+
+// IPVersion returns the IP version of the subnet prefix. It's empty if
+// the version is unknown because the prefix is empty or can't be parsed.
+func (subnet *Subnet) IPVersion() selvpcclient.IPVersion {
+	return selvpcclient.CIDRIPVersion(subnet.CIDR)
+}
+
+// Status represents a status of the subnet.
+type Status string
+
+const (
+	// StatusActive represents the subnet that is used by servers.
+	StatusActive Status = "ACTIVE"
+
+	// StatusDown represents the subnet that isn't used.
+	StatusDown Status = "DOWN"
+
+	// StatusError represents the subnet that failed to be created.
+	StatusError Status = "ERROR"
+)
+
+// IsKnown checks if the status is one of the statuses that are known to the
+// client. Unknown statuses returned by the API are kept as is.
+func (s Status) IsKnown() bool {
+	switch s {
+	case StatusActive, StatusDown, StatusError:
+		return true
+	}
+
+	return false
+}
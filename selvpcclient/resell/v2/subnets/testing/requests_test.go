@@ -2,10 +2,12 @@ package testing
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/subnets"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
@@ -450,3 +452,146 @@ func TestDeleteSubnetTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestSubnetOptsValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  subnets.SubnetOpts
+		valid bool
+	}{
+		{
+			name: "valid ipv6",
+			opts: subnets.SubnetOpts{
+				Subnets: []subnets.SubnetOpt{
+					{Region: "ru-1", Quantity: 1, Type: selvpcclient.IPv6, PrefixLength: 64},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "no subnets",
+			opts: subnets.SubnetOpts{},
+		},
+		{
+			name: "unknown type",
+			opts: subnets.SubnetOpts{
+				Subnets: []subnets.SubnetOpt{
+					{Region: "ru-1", Quantity: 1, Type: "ipv5", PrefixLength: 29},
+				},
+			},
+		},
+		{
+			name: "zero prefix length",
+			opts: subnets.SubnetOpts{
+				Subnets: []subnets.SubnetOpt{
+					{Region: "ru-1", Quantity: 1, Type: selvpcclient.IPv4},
+				},
+			},
+		},
+		{
+			name: "no region",
+			opts: subnets.SubnetOpts{
+				Subnets: []subnets.SubnetOpt{
+					{Quantity: 1, Type: selvpcclient.IPv4, PrefixLength: 29},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.opts.Validate()
+		if testCase.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", testCase.name, err)
+		}
+		if !testCase.valid && !selvpcclient.IsValidationError(err) {
+			t.Errorf("%s: expected ValidationError, but got %v", testCase.name, err)
+		}
+	}
+}
+
+func TestListSubnetsWithFilters(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var query string
+	testEnv.Mux.HandleFunc("/resell/v2/subnets", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		// The whole domain is returned to check the client-side filtering.
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, TestListSubnetsResponseRaw)
+	})
+
+	ctx := context.Background()
+	testCases := []struct {
+		opts          subnets.ListOpts
+		expectedQuery string
+		expectedIDs   []int
+	}{
+		{
+			opts:          subnets.ListOpts{Region: "ru-2", IPVersion: selvpcclient.IPv4},
+			expectedQuery: "region=ru-2&type=ipv4",
+			expectedIDs:   []int{112234},
+		},
+		{
+			opts:          subnets.ListOpts{IPVersion: selvpcclient.IPv6},
+			expectedQuery: "type=ipv6",
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, _, err := subnets.List(ctx, testEnv.Client, testCase.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query != testCase.expectedQuery {
+			t.Fatalf("expected %q query, but got %q", testCase.expectedQuery, query)
+		}
+
+		var actualIDs []int
+		for _, subnet := range actual {
+			actualIDs = append(actualIDs, subnet.ID)
+		}
+		if !reflect.DeepEqual(actualIDs, testCase.expectedIDs) {
+			t.Fatalf("expected %v subnets, but got %v", testCase.expectedIDs, actualIDs)
+		}
+	}
+}
+
+func TestSubnetIPVersion(t *testing.T) {
+	ipv4Subnet := subnets.Subnet{CIDR: "203.0.113.0/24"}
+	if version := ipv4Subnet.IPVersion(); version != selvpcclient.IPv4 {
+		t.Fatalf("expected ipv4, but got %s", version)
+	}
+	ipv6Subnet := subnets.Subnet{CIDR: "2001:db8::/64"}
+	if version := ipv6Subnet.IPVersion(); version != selvpcclient.IPv6 {
+		t.Fatalf("expected ipv6, but got %s", version)
+	}
+	for _, cidr := range []string{"", "203.0.113.0", "invalid/24"} {
+		subnet := subnets.Subnet{CIDR: cidr}
+		if version := subnet.IPVersion(); version != "" {
+			t.Fatalf("expected unknown IP version of the %q prefix, but got %s", cidr, version)
+		}
+	}
+}
+
+func TestListSubnetsUnknownIPVersion(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/subnets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"subnets": [{"id": 1, "cidr": ""}, {"id": 2, "cidr": "2001:db8::/64"}]}`)
+	})
+
+	// The subnet with the unknown IP version is left to the server-side
+	// filter.
+	actual, _, err := subnets.List(context.Background(), testEnv.Client, subnets.ListOpts{IPVersion: selvpcclient.IPv4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 1 || actual[0].ID != 1 {
+		t.Fatalf("expected only the subnet with the unknown IP version, but got %v", actual)
+	}
+}
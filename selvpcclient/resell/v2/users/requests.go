@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
@@ -14,7 +13,10 @@ const resourceURL = "users"
 
 // Get returns a single user by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*User, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,7 +39,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*U
 
 // List gets a list of users in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*User, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -58,8 +63,40 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient) ([]*User, *se
 	return result.Users, responseResult, nil
 }
 
+// Iterator iterates over users like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over all users in the current
+// domain.
+func NewIterator(client *selvpcclient.ServiceClient) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(string) (string, error) {
+			return client.BuildURL(resourceURL)
+		}),
+		Key: "users",
+		New: func() interface{} {
+			return &User{}
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current user.
+func (iterator *Iterator) Value() *User {
+	value, _ := iterator.ListIterator.Value().(*User)
+
+	return value
+}
+
 // Create requests a creation of the user.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts UserOpts) (*User, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	// Nest create options into the parent "user" JSON structure.
 	type createUser struct {
 		Options UserOpts `json:"user"`
@@ -70,7 +107,10 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
+	url, err := client.BuildURL(resourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -93,6 +133,10 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, createOpts
 
 // Update requests an update of the user referenced by its id.
 func Update(ctx context.Context, client *selvpcclient.ServiceClient, id string, updateOpts UserOpts) (*User, *selvpcclient.ResponseResult, error) {
+	if err := updateOpts.validateUpdate(); err != nil {
+		return nil, nil, err
+	}
+
 	// Nest update options into the parent "user" JSON structure.
 	type updateUser struct {
 		Options UserOpts `json:"user"`
@@ -103,7 +147,10 @@ func Update(ctx context.Context, client *selvpcclient.ServiceClient, id string,
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPatch, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -126,7 +173,10 @@ func Update(ctx context.Context, client *selvpcclient.ServiceClient, id string,
 
 // Delete deletes a single user by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
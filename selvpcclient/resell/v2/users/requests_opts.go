@@ -1,5 +1,7 @@
 package users
 
+import "github.com/selectel/go-selvpcclient/selvpcclient"
+
 // UserOpts represents options for the user Create and Update requests.
 type UserOpts struct {
 	// Name represents the name of a user.
@@ -11,3 +13,28 @@ type UserOpts struct {
 	// Enabled shows if user is active or it needs to be disabled.
 	Enabled *bool `json:"enabled,omitempty"`
 }
+
+// Validate checks that the options can be used to create a user: name and
+// password are required.
+func (opts *UserOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.Name == "" {
+		validationErr.Add("name", "is required")
+	}
+	if opts.Password == "" {
+		validationErr.Add("password", "is required")
+	}
+
+	return validationErr.Err()
+}
+
+// validateUpdate checks that the options can be used to update a user: at
+// least one field is required.
+func (opts *UserOpts) validateUpdate() error {
+	var validationErr selvpcclient.ValidationError
+	if opts.Name == "" && opts.Password == "" && opts.Enabled == nil {
+		validationErr.Add("user", "must contain at least one field to update")
+	}
+
+	return validationErr.Err()
+}
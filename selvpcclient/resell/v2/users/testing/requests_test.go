@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/users"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
@@ -556,3 +557,24 @@ func TestDeleteUserTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestUpdateUserEmptyOpts(t *testing.T) {
+	endpointCalled := false
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		endpointCalled = true
+	})
+
+	ctx := context.Background()
+	_, _, err := users.Update(ctx, testEnv.Client, "4b2e452ed4c940bd87a88499eaf14c4f", users.UserOpts{})
+
+	if endpointCalled {
+		t.Fatal("expected no request with empty options")
+	}
+	if !selvpcclient.IsValidationError(err) {
+		t.Fatalf("expected ValidationError, but got %v", err)
+	}
+}
@@ -5,16 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/selectel/go-selvpcclient/selvpcclient"
 )
 
 const resourceURL = "vrrp_subnets"
 
+// failedStatuses contains terminal statuses of the failed resources.
+var failedStatuses = []string{string(StatusError)}
+
 // Get returns a single VRRP subnet by its id.
 func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*VRRPSubnet, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, nil, err
@@ -37,15 +43,10 @@ func Get(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*V
 
 // List gets a list of VRRP subnets in the current domain.
 func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts) ([]*VRRPSubnet, *selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL}, "/")
-
-	queryParams, err := selvpcclient.BuildQueryParameters(opts)
+	url, err := client.BuildURLWithQuery(opts, resourceURL)
 	if err != nil {
 		return nil, nil, err
 	}
-	if queryParams != "" {
-		url = strings.Join([]string{url, queryParams}, "?")
-	}
 
 	responseResult, err := client.DoRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -64,18 +65,73 @@ func List(ctx context.Context, client *selvpcclient.ServiceClient, opts ListOpts
 		return nil, responseResult, err
 	}
 
-	return result.VRRPSubnets, responseResult, nil
+	filtered := make([]*VRRPSubnet, 0, len(result.VRRPSubnets))
+	for _, vrrpSubnet := range result.VRRPSubnets {
+		if opts.matches(vrrpSubnet) {
+			filtered = append(filtered, vrrpSubnet)
+		}
+	}
+
+	return filtered, responseResult, nil
+}
+
+// Iterator iterates over VRRP subnets like the selvpcclient.ListIterator.
+type Iterator struct {
+	*selvpcclient.ListIterator
+}
+
+// NewIterator returns a reference to the Iterator over VRRP subnets that match
+// the options. Pages of the opts.Limit size are requested if the limit is set.
+func NewIterator(client *selvpcclient.ServiceClient, opts ListOpts) *Iterator {
+	listIterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(client, func(marker string) (string, error) {
+			pageOpts := opts
+			if marker != "" {
+				pageOpts.Marker = marker
+			}
+			return client.BuildURLWithQuery(pageOpts, resourceURL)
+		}),
+		Key: "vrrpsubnets",
+		New: func() interface{} {
+			return &VRRPSubnet{}
+		},
+		Marker: func(value interface{}) string {
+			return strconv.Itoa(value.(*VRRPSubnet).ID)
+		},
+		Limit: opts.Limit,
+		Filter: func(value interface{}) bool {
+			return opts.matches(value.(*VRRPSubnet))
+		},
+	})
+
+	return &Iterator{listIterator}
+}
+
+// Value returns the current VRRP subnet.
+func (iterator *Iterator) Value() *VRRPSubnet {
+	value, _ := iterator.ListIterator.Value().(*VRRPSubnet)
+
+	return value
 }
 
 // Create requests a creation of the VRRP subnets in the specified project.
+// The Idempotency-Key header is sent if the context contains a key that is
+// set by the selvpcclient.WithIdempotencyKey.
 func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts VRRPSubnetOpts) ([]*VRRPSubnet, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	createVRRPSubnetsOpts := &createOpts
 	requestBody, err := json.Marshal(createVRRPSubnetsOpts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	url := strings.Join([]string{client.Endpoint, resourceURL, "projects", projectID}, "/")
+	url, err := client.BuildURL(resourceURL, "projects", projectID)
+	if err != nil {
+		return nil, nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, nil, err
@@ -96,9 +152,58 @@ func Create(ctx context.Context, client *selvpcclient.ServiceClient, projectID s
 	return result.VRRPSubnets, responseResult, nil
 }
 
+// CreateWithReconciliation requests a creation of the VRRP subnets like the
+// Create with the selvpcclient.CreateWithReconciliation, VRRP subnets are
+// matched by their master and slave regions and IP versions. Pass VRRP subnets
+// of the project from a previous List as the existing ones to skip the List
+// before the creation.
+func CreateWithReconciliation(ctx context.Context, client *selvpcclient.ServiceClient, projectID string, createOpts VRRPSubnetOpts, existing []*VRRPSubnet) ([]*VRRPSubnet, *selvpcclient.ResponseResult, error) {
+	if err := createOpts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	requested := make(map[string]int, len(createOpts.VRRPSubnets))
+	for _, opt := range createOpts.VRRPSubnets {
+		requested[opt.Regions.Master+"/"+opt.Regions.Slave+"/"+string(opt.Type)] += opt.Quantity
+	}
+	var createdVRRPSubnets []*VRRPSubnet
+	reconciled, responseResult, err := selvpcclient.CreateWithReconciliation(ctx, selvpcclient.ReconcileOpts{
+		List: func(ctx context.Context) ([]selvpcclient.ReconcileItem, error) {
+			vrrpSubnets, _, err := List(ctx, client, ListOpts{ProjectID: projectID})
+			if err != nil {
+				return nil, err
+			}
+			return reconcileItems(projectID, vrrpSubnets), nil
+		},
+		Create: func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+			var (
+				responseResult *selvpcclient.ResponseResult
+				err            error
+			)
+			createdVRRPSubnets, responseResult, err = Create(ctx, client, projectID, createOpts)
+			return responseResult, err
+		},
+		Requested: requested,
+		Existing:  reconcileItems(projectID, existing),
+	})
+	if reconciled == nil {
+		return createdVRRPSubnets, responseResult, err
+	}
+
+	reconciledVRRPSubnets := make([]*VRRPSubnet, 0, len(reconciled))
+	for _, value := range reconciled {
+		reconciledVRRPSubnets = append(reconciledVRRPSubnets, value.(*VRRPSubnet))
+	}
+
+	return reconciledVRRPSubnets, responseResult, nil
+}
+
 // Delete deletes a single VRRP subnet by its id.
 func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string) (*selvpcclient.ResponseResult, error) {
-	url := strings.Join([]string{client.Endpoint, resourceURL, id}, "/")
+	url, err := client.BuildURL(resourceURL, id)
+	if err != nil {
+		return nil, err
+	}
 	responseResult, err := client.DoRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
@@ -108,3 +213,58 @@ func Delete(ctx context.Context, client *selvpcclient.ServiceClient, id string)
 	}
 	return responseResult, err
 }
+
+// WaitForStatus polls the VRRP subnet until it reaches one of the provided
+// statuses and returns its last state. The selvpcclient.UnexpectedStatusError
+// is returned if the VRRP subnet reaches the ERROR status or is deleted.
+func WaitForStatus(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts, statuses ...Status) (*VRRPSubnet, error) {
+	target := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		target = append(target, string(status))
+	}
+	value, err := selvpcclient.WaitForResource(ctx, opts, resourceURL, id, getFunc(client, id), target, failedStatuses)
+	vrrpSubnet, _ := value.(*VRRPSubnet)
+
+	return vrrpSubnet, err
+}
+
+// WaitForDeleted polls the VRRP subnet until it doesn't exist anymore.
+func WaitForDeleted(ctx context.Context, client *selvpcclient.ServiceClient, id string, opts selvpcclient.WaitOpts) error {
+	_, err := WaitForStatus(ctx, client, id, opts, Status(selvpcclient.StatusDeleted))
+
+	return err
+}
+
+// getFunc returns the function that gets the VRRP subnet for the
+// selvpcclient.WaitForResource.
+func getFunc(client *selvpcclient.ServiceClient, id string) selvpcclient.ResourceGetFunc {
+	return func(ctx context.Context) (interface{}, string, error) {
+		vrrpSubnet, _, err := Get(ctx, client, id)
+		if err != nil || vrrpSubnet == nil {
+			return nil, "", err
+		}
+
+		return vrrpSubnet, string(vrrpSubnet.Status), nil
+	}
+}
+
+// reconcileItems converts VRRP subnets of the project for the
+// selvpcclient.CreateWithReconciliation. It returns nil for nil VRRP subnets.
+func reconcileItems(projectID string, vrrpSubnets []*VRRPSubnet) []selvpcclient.ReconcileItem {
+	if vrrpSubnets == nil {
+		return nil
+	}
+	items := make([]selvpcclient.ReconcileItem, 0, len(vrrpSubnets))
+	for _, vrrpSubnet := range vrrpSubnets {
+		if vrrpSubnet.ProjectID != projectID {
+			continue
+		}
+		items = append(items, selvpcclient.ReconcileItem{
+			ID:    strconv.Itoa(vrrpSubnet.ID),
+			Key:   vrrpSubnet.MasterRegion + "/" + vrrpSubnet.SlaveRegion + "/" + string(vrrpSubnet.IPVersion()),
+			Value: vrrpSubnet,
+		})
+	}
+
+	return items
+}
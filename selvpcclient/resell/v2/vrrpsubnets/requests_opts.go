@@ -1,6 +1,10 @@
 package vrrpsubnets
 
-import "github.com/selectel/go-selvpcclient/selvpcclient"
+import (
+	"fmt"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
 
 // VRRPSubnetOpts represents options for the VRRP subnets Create request.
 type VRRPSubnetOpts struct {
@@ -33,6 +37,74 @@ type VRRPRegionOpt struct {
 }
 
 // ListOpts represents options for the VRRP subnets List request.
+// Filters are sent as query parameters and are also applied to the
+// response in case the API ignores some of them.
 type ListOpts struct {
+	// Detailed requests additional details like associated servers.
 	Detailed bool `param:"detailed"`
+
+	// Limit sets the maximum number of VRRP subnets in the response.
+	// It's used as the page size by the Iterator.
+	Limit int `param:"limit"`
+
+	// Marker sets the ID of the last VRRP subnet of the previous page.
+	Marker string `param:"marker"`
+
+	// ProjectID filters VRRP subnets by the project.
+	ProjectID string `param:"project_id"`
+
+	// Region filters VRRP subnets by the master or the slave region.
+	Region string `param:"region"`
+
+	// Status filters VRRP subnets by the status.
+	Status Status `param:"status"`
+
+	// IPVersion filters VRRP subnets by the IP version.
+	IPVersion selvpcclient.IPVersion `param:"type"`
+}
+
+// Validate checks that every VRRP subnet has a positive quantity, different
+// master and slave regions, a known IP version and a prefix length that is
+// valid for it.
+func (opts *VRRPSubnetOpts) Validate() error {
+	var validationErr selvpcclient.ValidationError
+	if len(opts.VRRPSubnets) == 0 {
+		validationErr.Add("vrrp_subnets", "must contain at least one VRRP subnet")
+	}
+	for i, opt := range opts.VRRPSubnets {
+		field := fmt.Sprintf("vrrp_subnets[%d]", i)
+		if opt.Quantity < 1 {
+			validationErr.Add(field+".quantity", "must be positive")
+		}
+		if opt.Regions.Master == "" {
+			validationErr.Add(field+".regions.master", "is required")
+		}
+		if opt.Regions.Slave == "" {
+			validationErr.Add(field+".regions.slave", "is required")
+		}
+		if opt.Regions.Master != "" && opt.Regions.Master == opt.Regions.Slave {
+			validationErr.Add(field+".regions.slave", "must differ from the master region")
+		}
+		if !opt.Type.IsValid() {
+			validationErr.Addf(field+".type", "must be %s or %s", selvpcclient.IPv4, selvpcclient.IPv6)
+		} else if err := selvpcclient.ValidatePrefixLength(opt.Type, opt.PrefixLength); err != nil {
+			validationErr.Add(field+".prefix_length", err.Error())
+		}
+	}
+
+	return validationErr.Err()
+}
+
+// matches checks if the VRRP subnet satisfies all set filters. VRRP subnets
+// with an unknown IP version are left to the server-side filter.
+func (opts ListOpts) matches(vrrpSubnet *VRRPSubnet) bool {
+	switch {
+	case opts.ProjectID != "" && opts.ProjectID != vrrpSubnet.ProjectID,
+		opts.Region != "" && opts.Region != vrrpSubnet.MasterRegion && opts.Region != vrrpSubnet.SlaveRegion,
+		opts.Status != "" && opts.Status != vrrpSubnet.Status,
+		opts.IPVersion != "" && vrrpSubnet.IPVersion() != "" && opts.IPVersion != vrrpSubnet.IPVersion():
+		return false
+	}
+
+	return true
 }
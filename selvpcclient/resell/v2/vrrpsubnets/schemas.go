@@ -1,6 +1,7 @@
 package vrrpsubnets
 
 import (
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/servers"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/subnets"
 )
@@ -11,7 +12,7 @@ type VRRPSubnet struct {
 	ID int `json:"id"`
 
 	// Status shows if VRRP subnet is used.
-	Status string `json:"status"`
+	Status Status `json:"status"`
 
 	// Servers contains info about servers to which VRRP subnet is associated to.
 	Servers []servers.Server `json:"servers"`
@@ -32,3 +33,34 @@ type VRRPSubnet struct {
 	// ProjectID represents an associated Identity service project.
 	ProjectID string `json:"project_id"`
 }
+
+// IPVersion returns the IP version of the VRRP subnet prefix. It's empty if
+// the version is unknown because the prefix is empty or can't be parsed.
+func (vrrpSubnet *VRRPSubnet) IPVersion() selvpcclient.IPVersion {
+	return selvpcclient.CIDRIPVersion(vrrpSubnet.CIDR)
+}
+
+// Status represents a status of the VRRP subnet.
+type Status string
+
+const (
+	// StatusActive represents the VRRP subnet that is used by servers.
+	StatusActive Status = "ACTIVE"
+
+	// StatusDown represents the VRRP subnet that isn't used.
+	StatusDown Status = "DOWN"
+
+	// StatusError represents the VRRP subnet that failed to be created.
+	StatusError Status = "ERROR"
+)
+
+// IsKnown checks if the status is one of the statuses that are known to the
+// client. Unknown statuses returned by the API are kept as is.
+func (s Status) IsKnown() bool {
+	switch s {
+	case StatusActive, StatusDown, StatusError:
+		return true
+	}
+
+	return false
+}
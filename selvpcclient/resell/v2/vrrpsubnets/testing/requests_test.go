@@ -2,10 +2,12 @@ package testing
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"reflect"
 	"testing"
 
+	"github.com/selectel/go-selvpcclient/selvpcclient"
 	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/vrrpsubnets"
 	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
 )
@@ -414,3 +416,67 @@ func TestDeleteVRRPSubnetTimeoutError(t *testing.T) {
 		t.Fatal("expected error from the Delete method")
 	}
 }
+
+func TestCreateVRRPSubnetsInvalidOpts(t *testing.T) {
+	endpointCalled := false
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		endpointCalled = true
+	})
+
+	createOpts := vrrpsubnets.VRRPSubnetOpts{
+		VRRPSubnets: []vrrpsubnets.VRRPSubnetOpt{
+			{
+				Quantity: 0,
+				Regions: vrrpsubnets.VRRPRegionOpt{
+					Master: "ru-1",
+					Slave:  "ru-1",
+				},
+				Type:         selvpcclient.IPv4,
+				PrefixLength: 33,
+			},
+		},
+	}
+
+	ctx := context.Background()
+	_, _, err := vrrpsubnets.Create(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", createOpts)
+
+	if endpointCalled {
+		t.Fatal("expected no request with invalid options")
+	}
+	var validationErr *selvpcclient.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, but got %v", err)
+	}
+	expectedFields := []string{
+		"vrrp_subnets[0].quantity",
+		"vrrp_subnets[0].regions.slave",
+		"vrrp_subnets[0].prefix_length",
+	}
+	if len(validationErr.Errors) != len(expectedFields) {
+		t.Fatalf("expected %d invalid fields, but got %v", len(expectedFields), validationErr)
+	}
+	for i, field := range expectedFields {
+		if validationErr.Errors[i].Field != field {
+			t.Errorf("expected %s invalid field, but got %s", field, validationErr.Errors[i].Field)
+		}
+	}
+}
+
+func TestVRRPSubnetIPVersion(t *testing.T) {
+	testCases := map[string]selvpcclient.IPVersion{
+		"203.0.113.0/24": selvpcclient.IPv4,
+		"2001:db8::/64":  selvpcclient.IPv6,
+		"":               "",
+		"invalid/24":     "",
+	}
+	for cidr, expected := range testCases {
+		vrrpSubnet := vrrpsubnets.VRRPSubnet{CIDR: cidr}
+		if version := vrrpSubnet.IPVersion(); version != expected {
+			t.Errorf("expected %q IP version of the %q prefix, but got %q", expected, cidr, version)
+		}
+	}
+}
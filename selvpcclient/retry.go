@@ -0,0 +1,212 @@
+package selvpcclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// defaultRetryMaxAttempts represents the default number of attempts
+	// including the first request.
+	defaultRetryMaxAttempts = 3
+
+	// defaultRetryMinBackoff represents the default delay before the first retry.
+	defaultRetryMinBackoff = 500 * time.Millisecond
+
+	// defaultRetryMaxBackoff represents the default maximum delay between
+	// retries.
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy describes how the ServiceClient retries failed requests.
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) and requests
+// with the Idempotency-Key header are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts represents the maximum number of attempts including the first
+	// request. Values less than 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff represents the delay before the first retry.
+	// Every next delay is doubled.
+	MinBackoff time.Duration
+
+	// MaxBackoff represents the upper bound of the exponential delay.
+	// It doesn't limit the delay that is requested by the Retry-After header.
+	MaxBackoff time.Duration
+
+	// RetryStatusCodes contains HTTP status codes that should be retried.
+	// 429, 502, 503 and 504 status codes are retried if it's empty.
+	RetryStatusCodes []int
+
+	// RetryNonIdempotent allows to retry POST and PATCH requests.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a reference to the retry policy with the default
+// parameters.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+// defaultRetryStatusCodes contains status codes that are retried if the
+// policy doesn't specify them.
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// isIdempotent checks if a request with the provided method can be safely
+// repeated.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// maxAttempts returns the maximum number of attempts for the provided request.
+func (policy *RetryPolicy) maxAttempts(request *http.Request) int {
+	if policy == nil || policy.MaxAttempts < 2 {
+		return 1
+	}
+	retryable := isIdempotent(request.Method) || request.Header.Get(IdempotencyKeyHeader) != ""
+	if !retryable && !policy.RetryNonIdempotent {
+		return 1
+	}
+
+	return policy.MaxAttempts
+}
+
+// shouldRetry checks if the result of a single attempt should be retried.
+func (policy *RetryPolicy) shouldRetry(ctx context.Context, response *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isRetryableNetworkError(err)
+	}
+
+	statusCodes := policy.RetryStatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = defaultRetryStatusCodes
+	}
+	for _, statusCode := range statusCodes {
+		if response.StatusCode == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the delay before the provided retry that starts from 1.
+// Delay is randomized between the half and the full exponential value.
+// Value of the Retry-After header is used if it's bigger.
+func (policy *RetryPolicy) backoff(retry int, response *http.Response) time.Duration {
+	delay := policy.MinBackoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if policy.MaxBackoff > 0 && delay >= policy.MaxBackoff {
+			break
+		}
+	}
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	if delay > 0 {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+
+	if retryAfter := parseRetryAfter(response); retryAfter > delay {
+		delay = retryAfter
+	}
+
+	return delay
+}
+
+// parseRetryAfter returns the delay from the Retry-After header of the response.
+// The header can contain either a number of seconds or an HTTP date.
+func parseRetryAfter(response *http.Response) time.Duration {
+	if response == nil {
+		return 0
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// isRetryableNetworkError checks if the transport error is caused by a
+// dropped connection.
+func isRetryableNetworkError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// sleepContext waits for the provided duration or until the context is done.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// bufferRequestBody reads the provided request body into memory so
+// http.NewRequest can populate the request's GetBody and the body can be sent
+// again on every attempt.
+func bufferRequestBody(body io.Reader) (io.Reader, error) {
+	switch body.(type) {
+	case nil, *bytes.Reader, *bytes.Buffer, *strings.Reader:
+		return body, nil
+	}
+
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(b), nil
+}
+
+// drainBody reads the rest of the response body and closes it so the
+// underlying connection can be reused.
+func drainBody(body io.ReadCloser) {
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(body, 1<<16))
+	body.Close()
+}
@@ -11,11 +11,8 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"reflect"
-	"strconv"
 	"strings"
 	"time"
-	"unicode"
 )
 
 const (
@@ -60,32 +57,69 @@ const (
 	defaultExpectContinueTimeout = 1
 )
 
+// ErrInvalidPathSegment is returned by the ServiceClient's BuildURL for empty
+// IDs and for the "." and ".." path segments.
+var ErrInvalidPathSegment = errors.New("selvpcclient: invalid URL path segment")
+
 var (
 	errOptsIsNotStruct = errors.New("provided options is not a structure")
 	errServiceResponse = errors.New("status code from the server")
+	errTrailingData    = errors.New("invalid data after the top-level JSON value")
 )
 
+// HTTPTimeouts contains timeouts of the HTTP client.
+type HTTPTimeouts struct {
+	// Request represents the timeout of the whole HTTP request.
+	Request time.Duration
+
+	// Dial represents the timeout of the connection establishment.
+	Dial time.Duration
+
+	// TLSHandshake represents the timeout of the TLS handshake.
+	TLSHandshake time.Duration
+
+	// IdleConn represents the maximum amount of time an idle (keep-alive)
+	// connection will remain idle before closing itself.
+	IdleConn time.Duration
+}
+
+// DefaultHTTPTimeouts returns timeouts that are used by the NewHTTPClient.
+func DefaultHTTPTimeouts() HTTPTimeouts {
+	return HTTPTimeouts{
+		Request:      defaultHTTPTimeout * time.Second,
+		Dial:         defaultDialTimeout * time.Second,
+		TLSHandshake: defaultTLSHandshakeTimeout * time.Second,
+		IdleConn:     defaultIdleConnTimeout * time.Second,
+	}
+}
+
 // NewHTTPClient returns a reference to an initialized configured HTTP client.
 func NewHTTPClient() *http.Client {
+	return NewHTTPClientWithTimeouts(DefaultHTTPTimeouts())
+}
+
+// NewHTTPClientWithTimeouts returns a reference to an initialized configured
+// HTTP client with the provided timeouts.
+func NewHTTPClientWithTimeouts(timeouts HTTPTimeouts) *http.Client {
 	return &http.Client{
-		Timeout:   time.Second * defaultHTTPTimeout,
-		Transport: newHTTPTransport(),
+		Timeout:   timeouts.Request,
+		Transport: newHTTPTransport(timeouts),
 	}
 }
 
 // newHTTPTransport returns a reference to an initialized configured HTTP
 // transport.
-func newHTTPTransport() *http.Transport {
+func newHTTPTransport(timeouts HTTPTimeouts) *http.Transport {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
-			Timeout:   defaultDialTimeout * time.Second,
+			Timeout:   timeouts.Dial,
 			KeepAlive: defaultKeepaliveTimeout * time.Second,
 			DualStack: true,
 		}).DialContext,
 		MaxIdleConns:          defaultMaxIdleConns,
-		IdleConnTimeout:       defaultIdleConnTimeout * time.Second,
-		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout * time.Second,
+		IdleConnTimeout:       timeouts.IdleConn,
+		TLSHandshakeTimeout:   timeouts.TLSHandshake,
 		ExpectContinueTimeout: defaultExpectContinueTimeout * time.Second,
 	}
 }
@@ -99,93 +133,423 @@ type ServiceClient struct {
 	Endpoint string
 
 	// TokenID is a client authentication token.
+	// It's ignored if the TokenSource is set.
 	TokenID string
 
+	// TokenSource provides authentication tokens for every request.
+	TokenSource TokenSource
+
 	// UserAgent contains user agent that will be used in all requests.
 	UserAgent string
+
+	// RetryPolicy describes how failed requests should be retried.
+	// Requests aren't retried if it's nil.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter limits the rate of sent requests including retries.
+	// Requests aren't limited if it's nil.
+	RateLimiter *RateLimiter
+
+	// CircuitBreaker rejects requests with the ErrCircuitOpen while the
+	// endpoint is degraded.
+	// Requests aren't rejected if it's nil.
+	CircuitBreaker *CircuitBreaker
+
+	// DryRun enables the dry-run mode: mutations of the Resell resources are
+	// recorded to the plan instead of being sent. Results of the recorded
+	// requests can't be extracted, so functions that return resources return
+	// the ErrDryRun for them.
+	// Requests are sent as usual if it's nil.
+	DryRun *DryRunPlan
+
+	// Middlewares contains middlewares that wrap every request in the provided
+	// order.
+	Middlewares []Middleware
+
+	// Logger is used to report about internal events like retries.
+	// Nothing is logged if it's nil.
+	Logger Logger
+
+	// Debug enables logging of every request and response with the Logger.
+	Debug bool
+
+	// Tracer starts a span for every request.
+	// Requests aren't traced if it's nil.
+	Tracer Tracer
+
+	// Metrics records metrics of every request.
+	// Requests aren't measured if it's nil.
+	Metrics MetricsRecorder
+
+	// MaxResponseSize limits the decoded size of response bodies in bytes.
+	// The DefaultMaxResponseSize is used if it's zero, bodies aren't limited
+	// if it's negative.
+	MaxResponseSize int64
+
+	// AcceptGzip enables gzip compression of responses with the explicit
+	// Accept-Encoding header. It's useful for transports that don't
+	// request compression themselves like the http.Transport does.
+	AcceptGzip bool
+
+	// KeepResponseBody keeps response bodies that are decoded by the
+	// ExtractResult in memory, so they remain available through the RawBody.
+	// Bodies are decoded without buffering if it's false.
+	KeepResponseBody bool
+}
+
+// requestIDHeaders contains headers that can be used by the API to return
+// the request ID.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Openstack-Request-Id",
+	"X-Compute-Request-Id",
 }
 
 // ResponseResult represents a result of a HTTP request.
-// It embeddes standard http.Response and adds a custom error description.
+// It embeddes standard http.Response and adds a custom error description
+// and request metadata.
 type ResponseResult struct {
 	*http.Response
 
 	// Err contains error that can be provided to a caller.
 	Err error
+
+	// Method contains the HTTP method of the original request.
+	Method string
+
+	// URL contains the URL of the original request.
+	URL string
+
+	// RequestID contains the request ID that was returned by the server.
+	// It's empty if the server didn't return it.
+	RequestID string
+
+	// Latency contains time spent to get the response headers including
+	// all retries.
+	Latency time.Duration
+
+	// Attempts contains the number of sent requests including retries.
+	Attempts int
+
+	// DryRun shows that the request wasn't sent and the response is
+	// synthetic because the ServiceClient is in the dry-run mode.
+	// ExtractResult returns the ErrDryRun for such results.
+	DryRun bool
+
+	// body contains the buffered response body.
+	body []byte
+
+	// bodyBuffered shows if the response body was buffered.
+	bodyBuffered bool
+
+	// bodyErr contains the error that occurred while the body was read by
+	// the ExtractResult.
+	bodyErr error
+
+	// keepBody enables buffering of the body that is decoded by the
+	// ExtractResult.
+	keepBody bool
+}
+
+// RawBody returns the raw response body. The body is read once and buffered,
+// so it can be accessed any number of times together with the ExtractResult
+// and ExtractErr methods. It returns the ErrBodyConsumed if the body was
+// already decoded by the ExtractResult without the ServiceClient's
+// KeepResponseBody.
+func (result *ResponseResult) RawBody() ([]byte, error) {
+	if result.bodyErr != nil {
+		return nil, result.bodyErr
+	}
+	if !result.bodyBuffered {
+		if result.Response == nil || result.Body == nil {
+			return nil, nil
+		}
+		body, err := ioutil.ReadAll(result.Body)
+		result.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		result.body = body
+		result.bodyBuffered = true
+	}
+	result.Body = ioutil.NopCloser(bytes.NewReader(result.body))
+
+	return result.body, nil
+}
+
+// newBodyBuffer returns a buffer for the response body that is allocated in
+// advance if the body length is known and doesn't exceed the default limit.
+func (result *ResponseResult) newBodyBuffer() *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	if result.ContentLength > 0 && result.ContentLength <= DefaultMaxResponseSize {
+		buffer.Grow(int(result.ContentLength))
+	}
+
+	return buffer
+}
+
+// StreamUnmarshaler is implemented by types that can decode themselves
+// directly from the JSON decoder, for example token by token, without the
+// whole JSON value in memory.
+type StreamUnmarshaler interface {
+	UnmarshalJSONStream(decoder *json.Decoder) error
 }
 
 // ExtractResult allows to provide an object into which ResponseResult body will be extracted.
+// The body is decoded while it's read, so only the decoded object is kept in
+// memory. The body can be extracted only once unless it was buffered by the
+// RawBody or the ServiceClient's KeepResponseBody is set, the ErrBodyConsumed
+// is returned otherwise.
+// Objects that implement the StreamUnmarshaler are decoded with it instead of
+// the json.Unmarshaler.
+// It returns the ErrDryRun for synthetic results of the dry-run mode.
 func (result *ResponseResult) ExtractResult(to interface{}) error {
-	body, err := ioutil.ReadAll(result.Body)
-	defer result.Body.Close()
+	if result.DryRun {
+		return ErrDryRun
+	}
+	if result.bodyErr != nil {
+		return result.bodyErr
+	}
+	if result.bodyBuffered {
+		return json.Unmarshal(result.body, to)
+	}
+	if result.Response == nil || result.Body == nil {
+		return json.Unmarshal(nil, to)
+	}
+
+	body := result.Body
+	defer body.Close()
+	var reader io.Reader = body
+	if result.keepBody {
+		// Buffer the read bytes, the size is capped by the limited body that
+		// is set by the ServiceClient.
+		buffer := result.newBodyBuffer()
+		reader = io.TeeReader(body, buffer)
+		defer func() {
+			if _, err := io.Copy(buffer, body); err != nil {
+				result.bodyErr = err
+			}
+			if result.bodyErr == nil {
+				result.body = buffer.Bytes()
+				result.bodyBuffered = true
+				result.Body = ioutil.NopCloser(bytes.NewReader(result.body))
+			}
+		}()
+	} else {
+		defer func() {
+			if result.bodyErr == nil {
+				result.bodyErr = ErrBodyConsumed
+			}
+		}()
+	}
+
+	decoder := json.NewDecoder(reader)
+	var err error
+	if streamUnmarshaler, ok := to.(StreamUnmarshaler); ok {
+		err = streamUnmarshaler.UnmarshalJSONStream(decoder)
+	} else {
+		err = decoder.Decode(to)
+	}
 	if err != nil {
+		if err == io.EOF {
+			// Return the same error as the json.Unmarshal does for the empty body.
+			return json.Unmarshal(nil, to)
+		}
+		if errors.Is(err, ErrResponseTooLarge) {
+			result.bodyErr = err
+		}
 		return err
 	}
+	if _, err := decoder.Token(); err != io.EOF {
+		if err != nil {
+			if errors.Is(err, ErrResponseTooLarge) {
+				result.bodyErr = err
+			}
+			return err
+		}
+		return fmt.Errorf("selvpcclient: %w", errTrailingData)
+	}
 
-	err = json.Unmarshal(body, to)
-	return err
+	return nil
 }
 
 // ExtractErr build a string without whitespaces from the error body.
 // We don't unmarshal it into some struct because there are no strict error definition in the API.
 func (result *ResponseResult) ExtractErr() (string, error) {
-	body, err := ioutil.ReadAll(result.Body)
-	defer result.Body.Close()
+	body, err := result.RawBody()
 	if err != nil {
 		return "", err
 	}
 
-	resp := string(body)
+	return compactBody(body), nil
+}
 
-	var builder strings.Builder
-	builder.Grow(len(resp))
-	for _, ch := range resp {
-		if !unicode.IsSpace(ch) {
-			builder.WriteRune(ch)
+// BuildURL returns the URL of the provided path segments relative to the
+// ServiceClient's Endpoint. Every segment is escaped, so IDs and names can
+// contain any characters. Empty, "." and ".." segments are rejected with the
+// ErrInvalidPathSegment.
+func (client *ServiceClient) BuildURL(segments ...string) (string, error) {
+	escaped := make([]string, 0, len(segments)+1)
+	escaped = append(escaped, strings.TrimSuffix(client.Endpoint, "/"))
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w %q at position %d", ErrInvalidPathSegment, segment, i)
 		}
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+
+	return strings.Join(escaped, "/"), nil
+}
+
+// BuildURLWithQuery returns the URL of the provided path segments like the
+// BuildURL and appends the query parameters built from the provided options
+// with the BuildQueryParameters.
+func (client *ServiceClient) BuildURLWithQuery(opts interface{}, segments ...string) (string, error) {
+	u, err := client.BuildURL(segments...)
+	if err != nil {
+		return "", err
+	}
+	queryParams, err := BuildQueryParameters(opts)
+	if err != nil {
+		return "", err
+	}
+	if queryParams != "" {
+		u = strings.Join([]string{u, queryParams}, "?")
 	}
 
-	return builder.String(), nil
+	return u, nil
 }
 
 // DoRequest performs the HTTP request with the current ServiceClient's HTTPClient.
-// Authentication and optional headers will be added automatically.
+// Authentication and optional headers will be added automatically by the
+// default middlewares before the ServiceClient's Middlewares are called.
+// Failed requests are retried according to the ServiceClient's RetryPolicy.
+// Requests aren't sent while the ServiceClient's CircuitBreaker is open.
 func (client *ServiceClient) DoRequest(ctx context.Context, method, path string, body io.Reader) (*ResponseResult, error) {
+	// Buffer the body in advance so it can be sent again on retries.
+	body, err := bufferRequestBody(body)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare a HTTP request with the provided context.
 	request, err := http.NewRequest(method, path, body)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Set("User-Agent", client.UserAgent)
-	request.Header.Set("X-token", client.TokenID)
 	if body != nil {
 		request.Header.Set("Content-Type", "application/json")
 	}
+	if client.AcceptGzip {
+		request.Header.Set("Accept-Encoding", "gzip")
+	}
 	request = request.WithContext(ctx)
 
-	// Send HTTP request and populate the ResponseResult.
-	response, err := client.HTTPClient.Do(request)
+	handler := chainMiddlewares(client.send,
+		client.metricsMiddlewares(),
+		client.tracingMiddlewares(),
+		client.defaultMiddlewares(),
+		client.Middlewares,
+		client.debugMiddlewares(),
+		client.dryRunMiddlewares(),
+	)
+
+	return handler(request)
+}
+
+// send sends the prepared request and populates the ResponseResult.
+func (client *ServiceClient) send(request *http.Request) (*ResponseResult, error) {
+	start := time.Now()
+	response, attempts, err := client.doWithRetries(request)
 	if err != nil {
 		return nil, err
 	}
+	decompressResponse(response)
+	response.Body = newLimitedBody(response.Body, client.maxResponseSize())
 	responseResult := &ResponseResult{
-		response,
-		nil,
+		Response: response,
+		Method:   request.Method,
+		URL:      request.URL.String(),
+		Latency:  time.Since(start),
+		Attempts: attempts,
+		keepBody: client.KeepResponseBody,
+	}
+	for _, header := range requestIDHeaders {
+		if requestID := response.Header.Get(header); requestID != "" {
+			responseResult.RequestID = requestID
+			break
+		}
 	}
 
-	// Check status code and populate extended error message if it's possible.
+	// Check status code and populate the API error with the response body if it's possible.
 	if response.StatusCode >= 400 && response.StatusCode <= 599 {
-		extendedError, err := responseResult.ExtractErr()
+		errBody, err := responseResult.RawBody()
 		if err != nil {
-			responseResult.Err = fmt.Errorf("selvpcclient: got the %d %w", response.StatusCode, errServiceResponse)
-		} else {
-			responseResult.Err = fmt.Errorf("selvpcclient: got the %d %w: %s", response.StatusCode, errServiceResponse, extendedError)
+			errBody = nil
 		}
+		responseResult.Err = newAPIError(response.StatusCode, request.Method, request.URL.String(), errBody)
 	}
 
 	return responseResult, nil
 }
 
+// maxResponseSize returns the limit of the response body size.
+func (client *ServiceClient) maxResponseSize() int64 {
+	if client.MaxResponseSize == 0 {
+		return DefaultMaxResponseSize
+	}
+
+	return client.MaxResponseSize
+}
+
+// doWithRetries sends the request and repeats it according to the client's
+// RetryPolicy. It returns the last response and the number of attempts.
+func (client *ServiceClient) doWithRetries(request *http.Request) (*http.Response, int, error) {
+	ctx := request.Context()
+	maxAttempts := client.RetryPolicy.maxAttempts(request)
+
+	for attempt := 1; ; attempt++ {
+		attemptRequest := request
+		if attempt > 1 {
+			attemptRequest = request.Clone(ctx)
+			if request.GetBody != nil {
+				body, err := request.GetBody()
+				if err != nil {
+					return nil, attempt, err
+				}
+				attemptRequest.Body = body
+			}
+		}
+
+		if err := client.waitRateLimit(attemptRequest); err != nil {
+			return nil, attempt, err
+		}
+
+		generation, err := client.allowCircuit()
+		if err != nil {
+			return nil, attempt, err
+		}
+		response, err := client.HTTPClient.Do(attemptRequest)
+		client.doneCircuit(generation, response, err)
+		if attempt >= maxAttempts || !client.RetryPolicy.shouldRetry(ctx, response, err) {
+			return response, attempt, err
+		}
+
+		delay := client.RetryPolicy.backoff(attempt, response)
+		if response != nil {
+			drainBody(response.Body)
+			client.logf("selvpcclient: got the %d status code for %s %s, retrying in %s (attempt %d of %d)",
+				response.StatusCode, request.Method, request.URL, delay, attempt+1, maxAttempts)
+		} else {
+			client.logf("selvpcclient: got the %v error for %s %s, retrying in %s (attempt %d of %d)",
+				err, request.Method, request.URL, delay, attempt+1, maxAttempts)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
 // RFC3339NoZ describes a timestamp format used by some SelVPC responses.
 const RFC3339NoZ = "2006-01-02T15:04:05"
 
@@ -223,51 +587,16 @@ const (
 // IPVersion represents a type for the IP versions of the different Selectel VPC APIs.
 type IPVersion string
 
-// BuildQueryParameters converts provided options struct to the string of URL parameters.
-func BuildQueryParameters(opts interface{}) (string, error) {
-	optsValue := reflect.ValueOf(opts)
-	if optsValue.Kind() != reflect.Struct {
-		return "", errOptsIsNotStruct
-	}
-	optsType := reflect.TypeOf(opts)
-
-	params := url.Values{}
-
-	for i := 0; i < optsValue.NumField(); i++ {
-		fieldValue := optsValue.Field(i)
-		fieldType := optsType.Field(i)
-
-		queryTag := fieldType.Tag.Get("param")
-		if queryTag != "" {
-			if isZero(fieldValue) {
-				continue
-			}
-
-			tags := strings.Split(queryTag, ",")
-		loop:
-			switch fieldValue.Kind() {
-			case reflect.Ptr:
-				fieldValue = fieldValue.Elem()
-				goto loop
-			case reflect.String:
-				params.Add(tags[0], fieldValue.String())
-			case reflect.Int:
-				params.Add(tags[0], strconv.FormatInt(fieldValue.Int(), 10))
-			case reflect.Bool:
-				params.Add(tags[0], strconv.FormatBool(fieldValue.Bool()))
-			}
-		}
-	}
-
-	return params.Encode(), nil
-}
-
-// isZero checks if provided value is zero.
-func isZero(v reflect.Value) bool {
-	if v.Kind() == reflect.Ptr {
-		return v.IsNil()
+// CIDRIPVersion returns the IP version of the CIDR prefix like "192.0.2.0/24".
+// The empty IPVersion is returned if the prefix is empty or can't be parsed.
+func CIDRIPVersion(cidr string) IPVersion {
+	ip, _, err := net.ParseCIDR(cidr)
+	switch {
+	case err != nil:
+		return ""
+	case ip.To4() != nil:
+		return IPv4
 	}
-	z := reflect.Zero(v.Type())
 
-	return v.Interface() == z.Interface()
+	return IPv6
 }
@@ -0,0 +1,306 @@
+package testing
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+// newBodyTestClient returns the ServiceClient of the test server that responds
+// with the provided body.
+func newBodyTestClient(testEnv *testutils.TestEnv, body string) *selvpcclient.ServiceClient {
+	testEnv.Mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})
+
+	return &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+	}
+}
+
+func TestExtractResultConsumesBody(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	client := newBodyTestClient(testEnv, `{"id": "uuid"}`)
+
+	response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := response.ExtractResult(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != "uuid" {
+		t.Fatalf("expected uuid in the result, but got %s", result.ID)
+	}
+	if err := response.ExtractResult(&result); !errors.Is(err, selvpcclient.ErrBodyConsumed) {
+		t.Fatalf("expected ErrBodyConsumed, but got %v", err)
+	}
+	if _, err := response.RawBody(); !errors.Is(err, selvpcclient.ErrBodyConsumed) {
+		t.Fatalf("expected ErrBodyConsumed, but got %v", err)
+	}
+}
+
+func TestExtractResultAfterRawBody(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	client := newBodyTestClient(testEnv, `{"id": "uuid"}`)
+
+	response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := response.RawBody(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	for i := 0; i < 2; i++ {
+		if err := response.ExtractResult(&result); err != nil {
+			t.Fatalf("unable to extract the buffered result for the %d time: %v", i+1, err)
+		}
+	}
+	if result.ID != "uuid" {
+		t.Fatalf("expected uuid in the result, but got %s", result.ID)
+	}
+}
+
+func TestExtractResultKeepsRawBody(t *testing.T) {
+	for _, body := range []string{`{"id": "uuid"}`, `{"id": "uuid"`} {
+		t.Run(body, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			client := newBodyTestClient(testEnv, body)
+			client.KeepResponseBody = true
+
+			response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result streamItems
+			response.ExtractResult(&result)
+			rawBody, err := response.RawBody()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rawBody) != body {
+				t.Fatalf("expected %s raw body, but got %s", body, rawBody)
+			}
+			actualBody, err := ioutil.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actualBody) != body {
+				t.Fatalf("expected %s response body, but got %s", body, actualBody)
+			}
+		})
+	}
+}
+
+func TestExtractResultInvalidBody(t *testing.T) {
+	for _, body := range []string{``, `{"id": "uuid"`, `{"id": "uuid"} {}`} {
+		t.Run(body, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			client := newBodyTestClient(testEnv, body)
+
+			response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result struct {
+				ID string `json:"id"`
+			}
+			if err := response.ExtractResult(&result); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	body := `{"id": "uuid"}`
+	testCases := []struct {
+		name    string
+		limit   int64
+		tooLong bool
+	}{
+		{name: "default", limit: 0},
+		{name: "exact", limit: int64(len(body))},
+		{name: "exceeded", limit: int64(len(body)) - 1, tooLong: true},
+		{name: "unlimited", limit: -1},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			client := newBodyTestClient(testEnv, body)
+			client.MaxResponseSize = testCase.limit
+
+			for _, extract := range []func(*selvpcclient.ResponseResult) error{
+				func(response *selvpcclient.ResponseResult) error {
+					_, err := response.RawBody()
+					return err
+				},
+				func(response *selvpcclient.ResponseResult) error {
+					var result map[string]string
+					return response.ExtractResult(&result)
+				},
+			} {
+				response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = extract(response)
+				if testCase.tooLong != errors.Is(err, selvpcclient.ErrResponseTooLarge) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !testCase.tooLong && err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestAcceptGzip(t *testing.T) {
+	body := `{"id": "` + strings.Repeat("a", 1024) + `"}`
+
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			fmt.Fprint(w, body)
+			return
+		}
+		w.Header().Add("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		fmt.Fprint(writer, body)
+		writer.Close()
+	})
+
+	transport := &http.Transport{DisableCompression: true}
+	defer transport.CloseIdleConnections()
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{Transport: transport},
+		Endpoint:   testEnv.Server.URL,
+		AcceptGzip: true,
+	}
+
+	response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("expected no Content-Encoding header, but got %s", response.Header.Get("Content-Encoding"))
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := response.ExtractResult(&result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ID) != 1024 {
+		t.Fatalf("expected the decompressed ID of 1024 bytes, but got %d", len(result.ID))
+	}
+
+	// The limit is applied to the decompressed body.
+	client.MaxResponseSize = 512
+	response, err = client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := response.ExtractResult(&result); !errors.Is(err, selvpcclient.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, but got %v", err)
+	}
+	if _, err := response.RawBody(); !errors.Is(err, selvpcclient.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge from the RawBody, but got %v", err)
+	}
+}
+
+// streamItems decodes items of the "items" array one by one.
+type streamItems struct {
+	IDs []string
+}
+
+// UnmarshalJSONStream implements the selvpcclient.StreamUnmarshaler interface.
+func (result *streamItems) UnmarshalJSONStream(decoder *json.Decoder) error {
+	return selvpcclient.DecodeJSONArray(decoder, "items", func() error {
+		var item struct {
+			ID string `json:"id"`
+		}
+		if err := decoder.Decode(&item); err != nil {
+			return err
+		}
+		result.IDs = append(result.IDs, item.ID)
+
+		return nil
+	})
+}
+
+func TestExtractResultStreamUnmarshaler(t *testing.T) {
+	testCases := []struct {
+		body     string
+		expected []string
+		invalid  bool
+	}{
+		{body: `{"meta": {"total": 2}, "items": [{"id": "a"}, {"id": "b"}], "links": []}`, expected: []string{"a", "b"}},
+		{body: `{"items": null, "meta": {}}`},
+		{body: `{"meta": {}}`},
+		{body: ``, invalid: true},
+		{body: `{"items": {}}`, invalid: true},
+		{body: `{"items": [{"id": "a"}]} []`, invalid: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.body, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			client := newBodyTestClient(testEnv, testCase.body)
+
+			response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL+"/items", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result streamItems
+			err = response.ExtractResult(&result)
+			if testCase.invalid {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.IDs, testCase.expected) {
+				t.Fatalf("expected %v, but got %v", testCase.expected, result.IDs)
+			}
+		})
+	}
+}
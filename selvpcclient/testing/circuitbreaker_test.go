@@ -0,0 +1,128 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestCircuitBreaker(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	healthy := false
+	calls := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var states []selvpcclient.CircuitState
+	breaker := selvpcclient.NewCircuitBreaker(2, 20*time.Millisecond)
+	breaker.OnStateChange = func(_, to selvpcclient.CircuitState) {
+		states = append(states, to)
+	}
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:     &http.Client{},
+		Endpoint:       testEnv.Server.URL,
+		CircuitBreaker: breaker,
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if breaker.State() != selvpcclient.CircuitOpen {
+		t.Fatalf("expected open circuit, but got %s", breaker.State())
+	}
+
+	_, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if !errors.Is(err, selvpcclient.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, but got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected no requests to the open circuit, but got %d requests", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if breaker.State() != selvpcclient.CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, but got %s", breaker.State())
+	}
+
+	healthy = true
+	if _, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breaker.State() != selvpcclient.CircuitClosed {
+		t.Fatalf("expected closed circuit, but got %s", breaker.State())
+	}
+
+	expectedStates := []selvpcclient.CircuitState{
+		selvpcclient.CircuitOpen,
+		selvpcclient.CircuitHalfOpen,
+		selvpcclient.CircuitClosed,
+	}
+	if len(states) != len(expectedStates) {
+		t.Fatalf("expected %v state changes, but got %v", expectedStates, states)
+	}
+	for i := range states {
+		if states[i] != expectedStates[i] {
+			t.Fatalf("expected %v state changes, but got %v", expectedStates, states)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	breaker := selvpcclient.NewCircuitBreaker(1, 10*time.Millisecond)
+
+	generation, err := breaker.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	breaker.Done(generation, nil, errors.New("connection reset"))
+	if breaker.State() != selvpcclient.CircuitOpen {
+		t.Fatalf("expected open circuit, but got %s", breaker.State())
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	generation, err = breaker.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error for the trial request: %v", err)
+	}
+	if _, err := breaker.Allow(); !errors.Is(err, selvpcclient.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen for the second trial request, but got %v", err)
+	}
+	breaker.Done(generation, &http.Response{StatusCode: http.StatusBadGateway}, nil)
+	if breaker.State() != selvpcclient.CircuitOpen {
+		t.Fatalf("expected open circuit after the failed trial, but got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	breaker := selvpcclient.NewCircuitBreaker(1, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		generation, err := breaker.Allow()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		breaker.Done(generation, &http.Response{StatusCode: http.StatusNotFound}, nil)
+	}
+	generation, _ := breaker.Allow()
+	breaker.Done(generation, nil, context.Canceled)
+
+	if breaker.State() != selvpcclient.CircuitClosed {
+		t.Fatalf("expected closed circuit, but got %s", breaker.State())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -93,3 +94,141 @@ func TestDoPostRequest(t *testing.T) {
 		log.Fatalf("got %d response status, want 200", response.StatusCode)
 	}
 }
+
+func TestDoRequestResponseMetadata(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "req-1234")
+		fmt.Fprint(w, `{"id": "uuid"}`)
+	})
+
+	endpoint := testEnv.Server.URL + "/projects"
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:       &http.Client{},
+		Endpoint:         testEnv.Server.URL,
+		TokenID:          "token",
+		UserAgent:        "agent",
+		KeepResponseBody: true,
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Method != http.MethodGet {
+		t.Errorf("expected %s method, but got %s", http.MethodGet, response.Method)
+	}
+	if response.URL != endpoint {
+		t.Errorf("expected %s URL, but got %s", endpoint, response.URL)
+	}
+	if response.RequestID != "req-1234" {
+		t.Errorf("expected req-1234 request ID, but got %s", response.RequestID)
+	}
+	if response.Latency <= 0 {
+		t.Errorf("expected positive latency, but got %s", response.Latency)
+	}
+	if response.Attempts != 1 {
+		t.Errorf("expected 1 attempt, but got %d", response.Attempts)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	for i := 0; i < 2; i++ {
+		result.ID = ""
+		if err := response.ExtractResult(&result); err != nil {
+			t.Fatalf("unable to extract the result for the %d time: %v", i+1, err)
+		}
+		if result.ID != "uuid" {
+			t.Fatalf("expected uuid in the result, but got %s", result.ID)
+		}
+	}
+
+	rawBody, err := response.RawBody()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(rawBody) != `{"id": "uuid"}` {
+		t.Fatalf("expected raw body to be preserved, but got %s", rawBody)
+	}
+}
+
+func TestDoRequestErrorBodyPreserved(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "conflict"}`)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodDelete, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Err == nil {
+		t.Fatal("expected error in the response result")
+	}
+	extendedErr, err := response.ExtractErr()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extendedErr != `{"error":"conflict"}` {
+		t.Fatalf("expected error body to be preserved, but got %s", extendedErr)
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	client := &selvpcclient.ServiceClient{
+		Endpoint: "http://example.org/resell/v2/",
+	}
+
+	actual, err := client.BuildURL("keypairs", "my key/1", "users", "uuid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "http://example.org/resell/v2/keypairs/my%20key%2F1/users/uuid"
+	if actual != expected {
+		t.Fatalf("expected %s, but got %s", expected, actual)
+	}
+
+	for _, segment := range []string{"", ".", ".."} {
+		if _, err := client.BuildURL("projects", segment); !errors.Is(err, selvpcclient.ErrInvalidPathSegment) {
+			t.Errorf("expected ErrInvalidPathSegment for %q segment, but got %v", segment, err)
+		}
+	}
+}
+
+func TestBuildURLWithQuery(t *testing.T) {
+	client := &selvpcclient.ServiceClient{
+		Endpoint: "http://example.org/resell/v2",
+	}
+	opts := struct {
+		Detailed bool   `param:"detailed"`
+		Name     string `param:"name"`
+	}{
+		Detailed: true,
+		Name:     "a&b",
+	}
+
+	actual, err := client.BuildURLWithQuery(opts, "floatingips")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "http://example.org/resell/v2/floatingips?detailed=true&name=a%26b"
+	if actual != expected {
+		t.Fatalf("expected %s, but got %s", expected, actual)
+	}
+
+	if _, err := client.BuildURLWithQuery("opts", "floatingips"); err == nil {
+		t.Fatal("expected error for the non-struct options")
+	}
+}
@@ -0,0 +1,291 @@
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+const testProfileFile = `
+{
+    "default_profile": "main",
+    "profiles": {
+        "main": {
+            "token": "main-token",
+            "timeout": "30s",
+            "retries": 2
+        },
+        "staging": {
+            "token": "staging-token",
+            "endpoint": "https://example.org/resell/v2",
+            "timeout": "10"
+        }
+    }
+}
+`
+
+// setupConfigEnv writes the profile file, clears the config environment
+// variables and returns a function that restores them.
+func setupConfigEnv(t *testing.T, profileFile string) (string, func()) {
+	dir, err := ioutil.TempDir("", "selvpcclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(profileFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	envs := []string{
+		selvpcclient.EnvToken,
+		selvpcclient.EnvEndpoint,
+		selvpcclient.EnvTimeout,
+		selvpcclient.EnvRetries,
+		selvpcclient.EnvProfile,
+		selvpcclient.EnvConfigFile,
+	}
+	saved := make(map[string]string)
+	for _, env := range envs {
+		if value, ok := os.LookupEnv(env); ok {
+			saved[env] = value
+		}
+		os.Unsetenv(env)
+	}
+
+	return path, func() {
+		os.RemoveAll(dir)
+		for _, env := range envs {
+			os.Unsetenv(env)
+			if value, ok := saved[env]; ok {
+				os.Setenv(env, value)
+			}
+		}
+	}
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	path, teardown := setupConfigEnv(t, testProfileFile)
+	defer teardown()
+
+	cfg, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := selvpcclient.Config{
+		Token:   "main-token",
+		Timeout: 30 * time.Second,
+		Retries: 2,
+		Profile: "main",
+	}
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+	if cfg.RetryPolicy().MaxAttempts != 3 {
+		t.Errorf("expected 3 max attempts, but got %d", cfg.RetryPolicy().MaxAttempts)
+	}
+
+	os.Setenv(selvpcclient.EnvProfile, "staging")
+	cfg, err = selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = selvpcclient.Config{
+		Token:    "staging-token",
+		Endpoint: "https://example.org/resell/v2",
+		Timeout:  10 * time.Second,
+		Profile:  "staging",
+	}
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+	if cfg.RetryPolicy() != nil {
+		t.Errorf("expected no retry policy, but got %v", cfg.RetryPolicy())
+	}
+}
+
+func TestLoadConfigEnvPrecedence(t *testing.T) {
+	path, teardown := setupConfigEnv(t, testProfileFile)
+	defer teardown()
+
+	os.Setenv(selvpcclient.EnvConfigFile, path)
+	os.Setenv(selvpcclient.EnvProfile, "staging")
+	os.Setenv(selvpcclient.EnvToken, "env-token")
+	os.Setenv(selvpcclient.EnvTimeout, "1m")
+	os.Setenv(selvpcclient.EnvRetries, "0")
+
+	cfg, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The profile endpoint isn't used with the environment token.
+	expected := selvpcclient.Config{
+		Token:   "env-token",
+		Timeout: time.Minute,
+		Profile: "staging",
+	}
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+
+	os.Setenv(selvpcclient.EnvEndpoint, "https://example.com/resell/v2")
+	cfg, err = selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected.Endpoint = "https://example.com/resell/v2"
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+}
+
+func TestLoadConfigExplicitProfile(t *testing.T) {
+	path, teardown := setupConfigEnv(t, testProfileFile)
+	defer teardown()
+
+	os.Setenv(selvpcclient.EnvConfigFile, path)
+	os.Setenv(selvpcclient.EnvToken, "env-token")
+	os.Setenv(selvpcclient.EnvEndpoint, "https://example.com/resell/v2")
+	os.Setenv(selvpcclient.EnvTimeout, "1m")
+	os.Setenv(selvpcclient.EnvRetries, "3")
+
+	// The environment overrides the explicitly selected profile like the
+	// SELVPC_PROFILE one.
+	cfg, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Profile: "staging"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := selvpcclient.Config{
+		Token:    "env-token",
+		Endpoint: "https://example.com/resell/v2",
+		Timeout:  time.Minute,
+		Retries:  3,
+		Profile:  "staging",
+	}
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+
+	os.Unsetenv(selvpcclient.EnvToken)
+	os.Unsetenv(selvpcclient.EnvEndpoint)
+	cfg, err = selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Profile: "staging"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected.Token = "staging-token"
+	expected.Endpoint = "https://example.org/resell/v2"
+	if *cfg != expected {
+		t.Fatalf("expected %#v, but got %#v", expected, *cfg)
+	}
+}
+
+func TestLoadConfigProfileWithoutHome(t *testing.T) {
+	_, teardown := setupConfigEnv(t, testProfileFile)
+	defer teardown()
+
+	home, ok := os.LookupEnv("HOME")
+	os.Unsetenv("HOME")
+	defer func() {
+		if ok {
+			os.Setenv("HOME", home)
+		}
+	}()
+	if _, err := selvpcclient.DefaultConfigPath(); err == nil {
+		t.Skip("the home directory is known without HOME")
+	}
+	os.Setenv(selvpcclient.EnvToken, "env-token")
+
+	if _, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Profile: "staging"}); err == nil {
+		t.Fatal("expected error for the selected profile without the profile file")
+	}
+	os.Setenv(selvpcclient.EnvProfile, "staging")
+	if _, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{}); err == nil {
+		t.Fatal("expected error for the SELVPC_PROFILE profile without the profile file")
+	}
+
+	// The profile file is optional if no profile is selected.
+	os.Unsetenv(selvpcclient.EnvProfile)
+	cfg, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Token != "env-token" {
+		t.Fatalf("expected config from the environment, but got %#v", *cfg)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	path, teardown := setupConfigEnv(t, testProfileFile)
+	defer teardown()
+
+	testCases := []struct {
+		name string
+		opts selvpcclient.LoadConfigOpts
+		envs map[string]string
+	}{
+		{
+			name: "unknown profile",
+			opts: selvpcclient.LoadConfigOpts{Path: path, Profile: "unknown"},
+		},
+		{
+			name: "missing file",
+			opts: selvpcclient.LoadConfigOpts{Path: path + ".missing"},
+		},
+		{
+			name: "invalid retries",
+			opts: selvpcclient.LoadConfigOpts{Path: path},
+			envs: map[string]string{selvpcclient.EnvRetries: "-1"},
+		},
+		{
+			name: "invalid timeout",
+			opts: selvpcclient.LoadConfigOpts{Path: path},
+			envs: map[string]string{selvpcclient.EnvTimeout: "soon"},
+		},
+		{
+			name: "relative endpoint",
+			opts: selvpcclient.LoadConfigOpts{Path: path},
+			envs: map[string]string{
+				selvpcclient.EnvToken:    "env-token",
+				selvpcclient.EnvEndpoint: "example.org/resell",
+			},
+		},
+		{
+			name: "endpoint without token",
+			opts: selvpcclient.LoadConfigOpts{Path: path},
+			envs: map[string]string{selvpcclient.EnvEndpoint: "https://example.com/resell/v2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		for env, value := range testCase.envs {
+			os.Setenv(env, value)
+		}
+		if _, err := selvpcclient.LoadConfig(testCase.opts); err == nil {
+			t.Errorf("%s: expected error from the LoadConfig", testCase.name)
+		}
+		for env := range testCase.envs {
+			os.Unsetenv(env)
+		}
+	}
+}
+
+func TestLoadConfigWithoutToken(t *testing.T) {
+	path, teardown := setupConfigEnv(t, `{"profiles": {"other": {"token": "other-token"}}}`)
+	defer teardown()
+
+	if _, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Path: path}); err == nil {
+		t.Fatal("expected error from the LoadConfig without token")
+	}
+
+	os.Setenv(selvpcclient.EnvToken, "env-token")
+	cfg, err := selvpcclient.LoadConfig(selvpcclient.LoadConfigOpts{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Token != "env-token" || cfg.Profile != "" {
+		t.Fatalf("expected config from the environment, but got %#v", *cfg)
+	}
+}
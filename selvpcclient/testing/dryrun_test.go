@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/floatingips"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/users"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestDryRun(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var sent []string
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"floatingips": []}`)
+	})
+
+	plan := selvpcclient.NewDryRunPlan()
+	testEnv.Client.DryRun = plan
+
+	ctx := context.Background()
+	if _, _, err := floatingips.List(ctx, testEnv.Client, floatingips.ListOpts{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	createOpts := floatingips.FloatingIPOpts{
+		FloatingIPs: []floatingips.FloatingIPOpt{{Region: "ru-1", Quantity: 1}},
+	}
+	createdIPs, createResult, err := floatingips.Create(ctx, testEnv.Client, "49338ac045f448e294b25d013f890317", createOpts)
+	if !errors.Is(err, selvpcclient.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, but got %v", err)
+	}
+	if createdIPs != nil {
+		t.Errorf("expected no floating IPs in the dry-run mode, but got %v", createdIPs)
+	}
+	if createResult == nil || !createResult.DryRun {
+		t.Error("expected synthetic response for the Create request")
+	}
+	if _, _, err := users.Create(ctx, testEnv.Client, users.UserOpts{Name: "user", Password: "secret"}); !errors.Is(err, selvpcclient.ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, but got %v", err)
+	}
+	deleteResult, err := floatingips.Delete(ctx, testEnv.Client, "5232d5f3-4950-454b-bd41-78c5295622cd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleteResult.StatusCode != http.StatusNoContent {
+		t.Errorf("expected %d status for the Delete request, but got %d", http.StatusNoContent, deleteResult.StatusCode)
+	}
+
+	if len(sent) != 1 || sent[0] != "GET /resell/v2/floatingips" {
+		t.Fatalf("expected only the List request to be sent, but got %v", sent)
+	}
+
+	operations := plan.Operations()
+	if len(operations) != 3 {
+		t.Fatalf("expected 3 planned operations, but got %d", len(operations))
+	}
+	expectedNames := []string{"floatingips.create", "users.create", "floatingips.delete"}
+	for i, operation := range operations {
+		if name := operation.Resource + "." + operation.Name; name != expectedNames[i] {
+			t.Errorf("expected %s operation, but got %s", expectedNames[i], name)
+		}
+	}
+	if string(operations[0].Body) != `{"floatingips":[{"region":"ru-1","quantity":1}]}` {
+		t.Errorf("unexpected body of the planned operation: %s", operations[0].Body)
+	}
+	if strings.Contains(plan.String(), "secret") {
+		t.Errorf("expected redacted password in the plan, but got %s", plan)
+	}
+	if !strings.HasPrefix(plan.String(), "1. floatingips.create POST ") {
+		t.Errorf("unexpected printed plan: %s", plan)
+	}
+
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded struct {
+		Operations []selvpcclient.PlannedOperation `json:"operations"`
+	}
+	if err := json.Unmarshal(planJSON, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded.Operations) != 3 || decoded.Operations[2].Method != http.MethodDelete {
+		t.Fatalf("unexpected serialised plan: %s", planJSON)
+	}
+}
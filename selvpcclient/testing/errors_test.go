@@ -0,0 +1,223 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestAPIError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": {"code": "project_not_found", "message": "project doesn't exist"}}`)
+	})
+
+	endpoint := testEnv.Server.URL + "/"
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   endpoint,
+		TokenID:    "token",
+		UserAgent:  "agent",
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Err == nil {
+		t.Fatal("expected error in the response result")
+	}
+
+	var apiErr *selvpcclient.APIError
+	if !errors.As(response.Err, &apiErr) {
+		t.Fatalf("expected *selvpcclient.APIError, but got %T", response.Err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected %d status code, but got %d", http.StatusNotFound, apiErr.StatusCode)
+	}
+	if apiErr.Method != http.MethodGet {
+		t.Errorf("expected %s method, but got %s", http.MethodGet, apiErr.Method)
+	}
+	if apiErr.URL != endpoint {
+		t.Errorf("expected %s URL, but got %s", endpoint, apiErr.URL)
+	}
+	if apiErr.Code != "project_not_found" {
+		t.Errorf("expected project_not_found code, but got %s", apiErr.Code)
+	}
+	if apiErr.Message != "project doesn't exist" {
+		t.Errorf("expected error message from the body, but got %s", apiErr.Message)
+	}
+
+	expectedErr := `selvpcclient: got the 404 status code from the server: ` +
+		`{"error":{"code":"project_not_found","message":"projectdoesn'texist"}}`
+	if apiErr.Error() != expectedErr {
+		t.Errorf("expected %q error, but got %q", expectedErr, apiErr.Error())
+	}
+}
+
+func TestAPIErrorParseBody(t *testing.T) {
+	testCases := []struct {
+		body            string
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			body:         `{"error": "quota_exceeded"}`,
+			expectedCode: "quota_exceeded",
+		},
+		{
+			body:            `{"error": {"code": 409, "title": "Conflict", "message": "already exists"}}`,
+			expectedCode:    "409",
+			expectedMessage: "already exists",
+		},
+		{
+			body:            `{"code": "unauthorized", "message": "invalid token"}`,
+			expectedCode:    "unauthorized",
+			expectedMessage: "invalid token",
+		},
+		{
+			body: `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testEnv := testutils.SetupTestEnv()
+		body := testCase.body
+		testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, body)
+		})
+
+		client := &selvpcclient.ServiceClient{
+			HTTPClient: &http.Client{},
+			Endpoint:   testEnv.Server.URL,
+		}
+		response, err := client.DoRequest(context.Background(), http.MethodGet, testEnv.Server.URL, nil)
+		testEnv.TearDownTestEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var apiErr *selvpcclient.APIError
+		if !errors.As(response.Err, &apiErr) {
+			t.Fatalf("expected *selvpcclient.APIError, but got %T", response.Err)
+		}
+		if apiErr.Code != testCase.expectedCode {
+			t.Errorf("expected %q code for %s, but got %q", testCase.expectedCode, body, apiErr.Code)
+		}
+		if apiErr.Message != testCase.expectedMessage {
+			t.Errorf("expected %q message for %s, but got %q", testCase.expectedMessage, body, apiErr.Message)
+		}
+		if string(apiErr.Body) != body {
+			t.Errorf("expected %q raw body, but got %q", body, apiErr.Body)
+		}
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		err                   error
+		expectedNotFound      bool
+		expectedConflict      bool
+		expectedUnauthorized  bool
+		expectedQuotaExceeded bool
+	}{
+		{
+			name:             "not found",
+			err:              &selvpcclient.APIError{StatusCode: http.StatusNotFound},
+			expectedNotFound: true,
+		},
+		{
+			name:             "wrapped conflict",
+			err:              fmt.Errorf("cleanup: %w", &selvpcclient.APIError{StatusCode: http.StatusConflict}),
+			expectedConflict: true,
+		},
+		{
+			name:                 "unauthorized",
+			err:                  &selvpcclient.APIError{StatusCode: http.StatusUnauthorized},
+			expectedUnauthorized: true,
+		},
+		{
+			name: "quota exceeded",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusConflict,
+				Body:       []byte(`{"error": "quota_exceeded"}`),
+				Code:       "quota_exceeded",
+			},
+			expectedConflict:      true,
+			expectedQuotaExceeded: true,
+		},
+		{
+			name: "quota exceeded code in a bad request",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusBadRequest,
+				Code:       "QUOTA_EXCEEDED",
+			},
+			expectedQuotaExceeded: true,
+		},
+		{
+			name: "quota message in a forbidden error",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusForbidden,
+				Message:    "Quota of compute_cores is exceeded",
+			},
+			expectedQuotaExceeded: true,
+		},
+		{
+			name: "invalid quota value",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusBadRequest,
+				Body:       []byte(`{"error": "invalid quota value", "quotas": {"compute_cores": -1}}`),
+				Code:       "invalid quota value",
+			},
+		},
+		{
+			name: "quota in the raw body of a conflict",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusConflict,
+				Body:       []byte(`{"error": "project is locked", "quotas": {}}`),
+				Code:       "project is locked",
+			},
+			expectedConflict: true,
+		},
+		{
+			name: "quota message in a server error",
+			err: &selvpcclient.APIError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "quota service is unavailable",
+			},
+		},
+		{
+			name: "plain error",
+			err:  errors.New("got the 404"),
+		},
+		{
+			name: "nil error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if actual := selvpcclient.IsNotFound(testCase.err); actual != testCase.expectedNotFound {
+			t.Errorf("%s: expected IsNotFound to be %v", testCase.name, testCase.expectedNotFound)
+		}
+		if actual := selvpcclient.IsConflict(testCase.err); actual != testCase.expectedConflict {
+			t.Errorf("%s: expected IsConflict to be %v", testCase.name, testCase.expectedConflict)
+		}
+		if actual := selvpcclient.IsUnauthorized(testCase.err); actual != testCase.expectedUnauthorized {
+			t.Errorf("%s: expected IsUnauthorized to be %v", testCase.name, testCase.expectedUnauthorized)
+		}
+		if actual := selvpcclient.IsQuotaExceeded(testCase.err); actual != testCase.expectedQuotaExceeded {
+			t.Errorf("%s: expected IsQuotaExceeded to be %v", testCase.name, testCase.expectedQuotaExceeded)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first := selvpcclient.NewIdempotencyKey()
+	second := selvpcclient.NewIdempotencyKey()
+	if !uuidRe.MatchString(first) {
+		t.Fatalf("expected UUID idempotency key, but got %q", first)
+	}
+	if first == second {
+		t.Fatalf("expected different idempotency keys, but got %q twice", first)
+	}
+}
+
+func TestEnsureIdempotencyKey(t *testing.T) {
+	ctx := selvpcclient.WithIdempotencyKey(context.Background(), "key")
+	if key := selvpcclient.IdempotencyKey(selvpcclient.EnsureIdempotencyKey(ctx)); key != "key" {
+		t.Fatalf("expected the existing idempotency key, but got %q", key)
+	}
+
+	ctx = selvpcclient.EnsureIdempotencyKey(context.Background())
+	if selvpcclient.IdempotencyKey(ctx) == "" {
+		t.Fatal("expected a new idempotency key")
+	}
+}
+
+func TestDoRequestIdempotencyKeyRetry(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	var keys []string
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(selvpcclient.IdempotencyKeyHeader))
+		if len(keys) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RetryPolicy: newTestRetryPolicy(),
+	}
+
+	ctx := selvpcclient.WithIdempotencyKey(context.Background(), "key")
+	response, err := client.DoRequest(ctx, http.MethodPost, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("expected %d status, but got %d", http.StatusCreated, response.StatusCode)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 attempts, but got %d", len(keys))
+	}
+	for _, key := range keys {
+		if key != "key" {
+			t.Fatalf("expected key idempotency key, but got %q", key)
+		}
+	}
+}
+
+func TestIsAmbiguousError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/dropped", func(w http.ResponseWriter, r *http.Request) {
+		// Close the connection after the request is received.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+	}
+	_, droppedErr := client.DoRequest(context.Background(), http.MethodPost, testEnv.Server.URL+"/dropped", nil)
+	if droppedErr == nil {
+		t.Fatal("expected transport error")
+	}
+
+	closedEnv := testutils.SetupTestEnv()
+	closedURL := closedEnv.Server.URL
+	closedEnv.TearDownTestEnv()
+	_, refusedErr := client.DoRequest(context.Background(), http.MethodPost, closedURL, nil)
+	if refusedErr == nil {
+		t.Fatal("expected transport error")
+	}
+
+	dnsErr := &url.Error{
+		Op:  http.MethodPost,
+		URL: "https://unknown.invalid",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "unknown.invalid"}},
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"dropped connection", droppedErr, true},
+		{"refused connection", refusedErr, false},
+		{"dns", dnsErr, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"server", &selvpcclient.APIError{StatusCode: http.StatusBadGateway}, true},
+		{"client", &selvpcclient.APIError{StatusCode: http.StatusConflict}, false},
+		{"other", errors.New("invalid options"), false},
+	}
+	for _, testCase := range testCases {
+		if actual := selvpcclient.IsAmbiguousError(testCase.err); actual != testCase.expected {
+			t.Errorf("%s: expected %t, but got %t", testCase.name, testCase.expected, actual)
+		}
+	}
+}
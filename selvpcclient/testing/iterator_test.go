@@ -0,0 +1,285 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+type iteratorItem struct {
+	ID string `json:"id"`
+}
+
+// newTestListIterator returns the ListIterator over the "items" array of the
+// test server that passes the marker and the limit as query parameters.
+func newTestListIterator(testEnv *testutils.TestEnv, limit int) *selvpcclient.ListIterator {
+	return selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: func(ctx context.Context, marker string) (*selvpcclient.ResponseResult, error) {
+			opts := struct {
+				Limit  int    `param:"limit"`
+				Marker string `param:"marker"`
+			}{Limit: limit, Marker: marker}
+			url, err := testEnv.Client.BuildURLWithQuery(opts, "items")
+			if err != nil {
+				return nil, err
+			}
+
+			return testEnv.Client.DoRequest(ctx, http.MethodGet, url, nil)
+		},
+		Key: "items",
+		New: func() interface{} {
+			return &iteratorItem{}
+		},
+		Marker: func(value interface{}) string {
+			return value.(*iteratorItem).ID
+		},
+		Limit: limit,
+	})
+}
+
+// collectIteratorIDs returns IDs of all items of the iterator.
+func collectIteratorIDs(t *testing.T, iterator *selvpcclient.ListIterator) []string {
+	t.Helper()
+
+	var ids []string
+	for iterator.Next(context.Background()) {
+		ids = append(ids, iterator.Value().(*iteratorItem).ID)
+	}
+
+	return ids
+}
+
+func TestListIteratorPages(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var markers []string
+	testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+		marker := r.URL.Query().Get("marker")
+		markers = append(markers, marker)
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("expected the limit of 2, but got %q", r.URL.Query().Get("limit"))
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		switch marker {
+		case "":
+			fmt.Fprint(w, `{"meta": {"total": 5}, "items": [{"id": "a"}, {"id": "b"}]}`)
+		case "b":
+			fmt.Fprint(w, `{"items": [{"id": "c"}, {"id": "d"}], "meta": {"total": 5}}`)
+		default:
+			fmt.Fprint(w, `{"items": [{"id": "e"}]}`)
+		}
+	})
+
+	iterator := newTestListIterator(testEnv, 2)
+	actual := collectIteratorIDs(t, iterator)
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b", "c", "d", "e"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, but got %v", expected, actual)
+	}
+	expectedMarkers := []string{"", "b", "d"}
+	if !reflect.DeepEqual(markers, expectedMarkers) {
+		t.Fatalf("expected %v markers, but got %v", expectedMarkers, markers)
+	}
+	if iterator.Next(context.Background()) {
+		t.Fatal("expected no more items")
+	}
+}
+
+func TestListIteratorFilter(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	var markers []string
+	testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+		marker := r.URL.Query().Get("marker")
+		markers = append(markers, marker)
+
+		w.Header().Add("Content-Type", "application/json")
+		switch marker {
+		case "":
+			fmt.Fprint(w, `{"items": [{"id": "a"}, {"id": "b"}]}`)
+		case "b":
+			fmt.Fprint(w, `{"items": [{"id": "c"}, {"id": "d"}]}`)
+		default:
+			fmt.Fprint(w, `{"items": []}`)
+		}
+	})
+
+	iterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{
+		Fetch: selvpcclient.FetchURL(testEnv.Client, func(marker string) (string, error) {
+			opts := struct {
+				Limit  int    `param:"limit"`
+				Marker string `param:"marker"`
+			}{Limit: 2, Marker: marker}
+			return testEnv.Client.BuildURLWithQuery(opts, "items")
+		}),
+		Key: "items",
+		New: func() interface{} {
+			return &iteratorItem{}
+		},
+		Marker: func(value interface{}) string {
+			return value.(*iteratorItem).ID
+		},
+		Limit: 2,
+		Filter: func(value interface{}) bool {
+			id := value.(*iteratorItem).ID
+			return id != "b" && id != "d"
+		},
+	})
+	actual := collectIteratorIDs(t, iterator)
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skipped items are still used for markers of the next pages.
+	expected := []string{"a", "c"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, but got %v", expected, actual)
+	}
+	expectedMarkers := []string{"", "b", "d"}
+	if !reflect.DeepEqual(markers, expectedMarkers) {
+		t.Fatalf("expected %v markers, but got %v", expectedMarkers, markers)
+	}
+}
+
+func TestListIteratorIgnoredLimit(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	calls := 0
+	testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items": [{"id": "a"}, {"id": "b"}, {"id": "c"}]}`)
+	})
+
+	iterator := newTestListIterator(testEnv, 2)
+	actual := collectIteratorIDs(t, iterator)
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, but got %v", expected, actual)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 request, but got %d", calls)
+	}
+}
+
+func TestListIteratorEmpty(t *testing.T) {
+	for _, body := range []string{`{"items": []}`, `{"items": null}`, `{"other": [1]}`, ``} {
+		t.Run(body, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			testEnv.NewTestResellV2Client()
+
+			testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			})
+
+			iterator := newTestListIterator(testEnv, 0)
+			if actual := collectIteratorIDs(t, iterator); len(actual) != 0 {
+				t.Fatalf("expected no items, but got %v", actual)
+			}
+			if err := iterator.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestListIteratorHTTPError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	iterator := newTestListIterator(testEnv, 0)
+	if iterator.Next(context.Background()) {
+		t.Fatal("expected no items")
+	}
+	var apiErr *selvpcclient.APIError
+	if !errors.As(iterator.Err(), &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected the 400 APIError, but got %v", iterator.Err())
+	}
+	if iterator.Result() == nil {
+		t.Fatal("expected the result of the failed page")
+	}
+}
+
+func TestListIteratorInvalidBody(t *testing.T) {
+	for _, body := range []string{`[]`, `{"items": {}}`, `{"items": [{"id": 1}]}`, `{"items": [{"id": "a"}`} {
+		t.Run(body, func(t *testing.T) {
+			testEnv := testutils.SetupTestEnv()
+			defer testEnv.TearDownTestEnv()
+			testEnv.NewTestResellV2Client()
+
+			testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				fmt.Fprint(w, body)
+			})
+
+			iterator := newTestListIterator(testEnv, 0)
+			collectIteratorIDs(t, iterator)
+			if iterator.Err() == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestListIteratorClose(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.NewTestResellV2Client()
+
+	testEnv.Mux.HandleFunc("/resell/v2/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items": [{"id": "a"}, {"id": "b"}]}`)
+	})
+
+	iterator := newTestListIterator(testEnv, 0)
+	if !iterator.Next(context.Background()) {
+		t.Fatalf("expected the first item, but got %v", iterator.Err())
+	}
+	if err := iterator.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if iterator.Next(context.Background()) {
+		t.Fatal("expected no items after the Close")
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListIteratorInvalidOpts(t *testing.T) {
+	iterator := selvpcclient.NewListIterator(selvpcclient.ListIteratorOpts{Key: "items"})
+	if iterator.Next(context.Background()) {
+		t.Fatal("expected no items")
+	}
+	if iterator.Err() == nil {
+		t.Fatal("expected an error without the fetch function")
+	}
+}
@@ -0,0 +1,128 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestDebugLog(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": {"id": "secret-token-id"}}`)
+	})
+
+	var logBuffer bytes.Buffer
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		TokenID:    "secret-auth-token",
+		UserAgent:  "agent",
+		Logger:     log.New(&logBuffer, "", 0),
+		Debug:      true,
+	}
+
+	requestBody := `{"user": {"name": "user", "password": "secret-password"}}`
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodPost, testEnv.Server.URL+"/tokens", strings.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Token struct {
+			ID string `json:"id"`
+		} `json:"token"`
+	}
+	if err := response.ExtractResult(&result); err != nil {
+		t.Fatalf("unable to extract the result after logging: %v", err)
+	}
+	if result.Token.ID != "secret-token-id" {
+		t.Fatalf("expected secret-token-id token in the result, but got %s", result.Token.ID)
+	}
+
+	logOutput := logBuffer.String()
+	for _, secret := range []string{"secret-auth-token", "secret-password", "secret-token-id"} {
+		if strings.Contains(logOutput, secret) {
+			t.Errorf("expected %s to be redacted in the log:\n%s", secret, logOutput)
+		}
+	}
+	for _, expected := range []string{"POST " + testEnv.Server.URL + "/tokens", "201", "User-Agent: agent", `{"name": "user",`} {
+		if !strings.Contains(logOutput, expected) {
+			t.Errorf("expected %q in the log:\n%s", expected, logOutput)
+		}
+	}
+}
+
+func TestDoRequestDebugLogRedactsURL(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var logBuffer bytes.Buffer
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		Logger:     log.New(&logBuffer, "", 0),
+		Debug:      true,
+	}
+
+	_, err := client.DoRequest(context.Background(), http.MethodDelete, testEnv.Server.URL+"/tokens/secret-token-id", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logOutput := logBuffer.String()
+	if strings.Contains(logOutput, "secret-token-id") {
+		t.Errorf("expected token ID to be redacted in the log:\n%s", logOutput)
+	}
+	if !strings.Contains(logOutput, "DELETE "+testEnv.Server.URL+"/tokens/[REDACTED]") {
+		t.Errorf("expected redacted URL in the log:\n%s", logOutput)
+	}
+}
+
+func TestDoRequestDebugLogKeepsBody(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"quota": {"value": 12345678901234567890, "name": "<a&b>"}}`)
+	})
+
+	var logBuffer bytes.Buffer
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		Logger:     log.New(&logBuffer, "", 0),
+		Debug:      true,
+	}
+
+	requestBody := `{"zone": "ru-1a", "password" : "secret-password", "count": 10}`
+	_, err := client.DoRequest(context.Background(), http.MethodPost, testEnv.Server.URL+"/items", strings.NewReader(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logOutput := logBuffer.String()
+	expected := []string{
+		`Body: {"zone": "ru-1a", "password" : "[REDACTED]", "count": 10}`,
+		`Body: {"quota": {"value": 12345678901234567890, "name": "<a&b>"}}`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(logOutput, line) {
+			t.Errorf("expected %q in the log:\n%s", line, logOutput)
+		}
+	}
+}
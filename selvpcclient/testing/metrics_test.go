@@ -0,0 +1,90 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+// fakeMetricsRecorder records all observed request metrics.
+type fakeMetricsRecorder struct {
+	metrics []selvpcclient.RequestMetrics
+}
+
+func (recorder *fakeMetricsRecorder) ObserveRequest(metrics selvpcclient.RequestMetrics) {
+	recorder.metrics = append(recorder.metrics, metrics)
+}
+
+func TestDoRequestMetrics(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/resell/v2/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	testEnv.Mux.HandleFunc("/resell/v2/projects/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	recorder := &fakeMetricsRecorder{}
+	endpoint := testEnv.Server.URL + "/resell/v2"
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   endpoint,
+		Metrics:    recorder,
+	}
+
+	ctx := context.Background()
+	if _, err := client.DoRequest(ctx, http.MethodGet, endpoint+"/projects", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.DoRequest(ctx, http.MethodGet, endpoint+"/projects/49338ac0", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.metrics) != 2 {
+		t.Fatalf("expected 2 observed requests, but got %d", len(recorder.metrics))
+	}
+
+	listMetrics := recorder.metrics[0]
+	if listMetrics.Operation.Resource != "projects" || listMetrics.Operation.Name != selvpcclient.OperationList {
+		t.Errorf("expected projects.list operation, but got %s", listMetrics.Operation)
+	}
+	if listMetrics.StatusClass != "2xx" || listMetrics.Err != nil {
+		t.Errorf("expected successful 2xx request, but got %s class and %v error", listMetrics.StatusClass, listMetrics.Err)
+	}
+
+	getMetrics := recorder.metrics[1]
+	if getMetrics.StatusCode != http.StatusNotFound || getMetrics.StatusClass != "4xx" {
+		t.Errorf("expected 404 status in the 4xx class, but got %d and %s", getMetrics.StatusCode, getMetrics.StatusClass)
+	}
+	if !selvpcclient.IsNotFound(getMetrics.Err) {
+		t.Errorf("expected not found error in metrics, but got %v", getMetrics.Err)
+	}
+}
+
+func TestDoRequestMetricsTransportError(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	testEnv.Server.Close()
+	defer testEnv.TearDownTestEnv()
+
+	recorder := &fakeMetricsRecorder{}
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		Metrics:    recorder,
+	}
+
+	ctx := context.Background()
+	if _, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL+"/traffic", nil); err == nil {
+		t.Fatal("expected error from the closed server")
+	}
+	if len(recorder.metrics) != 1 {
+		t.Fatalf("expected 1 observed request, but got %d", len(recorder.metrics))
+	}
+	if recorder.metrics[0].StatusClass != selvpcclient.StatusClassError || recorder.metrics[0].Err == nil {
+		t.Fatalf("expected transport error in metrics, but got %+v", recorder.metrics[0])
+	}
+}
@@ -0,0 +1,94 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestMiddlewares(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "agent" {
+			t.Errorf("expected agent User-Agent header, but got %s", r.Header.Get("User-Agent"))
+		}
+		if r.Header.Get("X-token") != "token" {
+			t.Errorf("expected token X-token header, but got %s", r.Header.Get("X-token"))
+		}
+		if r.Header.Get("X-Request-Source") != "tests" {
+			t.Errorf("expected tests X-Request-Source header, but got %s", r.Header.Get("X-Request-Source"))
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	var calls []string
+	tracingMiddleware := func(name string) selvpcclient.Middleware {
+		return func(next selvpcclient.Handler) selvpcclient.Handler {
+			return func(request *http.Request) (*selvpcclient.ResponseResult, error) {
+				calls = append(calls, name+" before")
+				result, err := next(request)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.StatusCode != http.StatusAccepted {
+					t.Errorf("expected %d status in the middleware, but got %d", http.StatusAccepted, result.StatusCode)
+				}
+				calls = append(calls, name+" after")
+				return result, err
+			}
+		}
+	}
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		TokenID:    "token",
+		UserAgent:  "agent",
+	}
+	client.Use(
+		tracingMiddleware("first"),
+		selvpcclient.HeaderMiddleware("X-Request-Source", "tests"),
+		tracingMiddleware("second"),
+	)
+
+	ctx := context.Background()
+	_, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"first before", "second before", "second after", "first after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v middleware calls, but got %v", expected, calls)
+	}
+}
+
+func TestDoRequestMiddlewareOverridesDefaults(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "custom-agent" {
+			t.Errorf("expected custom-agent User-Agent header, but got %s", r.Header.Get("User-Agent"))
+		}
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		UserAgent:  "agent",
+		Middlewares: []selvpcclient.Middleware{
+			selvpcclient.UserAgentMiddleware("custom-agent"),
+		},
+	}
+
+	ctx := context.Background()
+	_, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
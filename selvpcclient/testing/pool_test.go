@@ -0,0 +1,188 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/resell/v2/quotas"
+)
+
+func TestClientPoolRun(t *testing.T) {
+	newServer := func(status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/quotas/free" {
+				t.Errorf("unexpected request path %s", r.URL.Path)
+			}
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"quotas": {"compute_cores": [{"region": "ru-1", "zone": "ru-1a", "value": 10}]}}`)
+		}))
+	}
+	firstServer := newServer(http.StatusOK)
+	defer firstServer.Close()
+	secondServer := newServer(http.StatusOK)
+	defer secondServer.Close()
+	failedServer := newServer(http.StatusUnauthorized)
+	defer failedServer.Close()
+
+	pool := selvpcclient.NewClientPool(2)
+	for account, server := range map[string]*httptest.Server{
+		"first":  firstServer,
+		"second": secondServer,
+		"failed": failedServer,
+	} {
+		client := &selvpcclient.ServiceClient{
+			HTTPClient: &http.Client{},
+			Endpoint:   server.URL,
+			TokenID:    account,
+		}
+		if err := pool.Add(account, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := pool.Add("first", &selvpcclient.ServiceClient{}); err == nil {
+		t.Fatal("expected error for the duplicate account")
+	}
+
+	ctx := context.Background()
+	results, err := pool.Run(ctx, func(ctx context.Context, _ string, client *selvpcclient.ServiceClient) (interface{}, error) {
+		freeQuotas, _, err := quotas.GetFree(ctx, client)
+		return freeQuotas, err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, but got %d", len(results))
+	}
+	for i, account := range []string{"failed", "first", "second"} {
+		if results[i].Account != account {
+			t.Errorf("expected %s account in the %d result, but got %s", account, i, results[i].Account)
+		}
+	}
+	for _, account := range []string{"first", "second"} {
+		result, _ := results.Get(account)
+		if result.Err != nil {
+			t.Errorf("unexpected error for the %s account: %v", account, result.Err)
+		}
+		if freeQuotas, ok := result.Value.([]*quotas.Quota); !ok || len(freeQuotas) != 1 {
+			t.Errorf("expected free quotas for the %s account, but got %#v", account, result.Value)
+		}
+	}
+
+	var poolErr *selvpcclient.PoolError
+	if !errors.As(results.Err(), &poolErr) {
+		t.Fatalf("expected PoolError, but got %v", results.Err())
+	}
+	if len(poolErr.Errors) != 1 || !selvpcclient.IsUnauthorized(poolErr.Errors["failed"]) {
+		t.Fatalf("expected unauthorized error for the failed account, but got %v", poolErr)
+	}
+}
+
+func TestClientPoolParallelism(t *testing.T) {
+	pool := selvpcclient.NewClientPool(2)
+	for i := 0; i < 6; i++ {
+		if err := pool.Add(fmt.Sprintf("account-%d", i), &selvpcclient.ServiceClient{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var (
+		mu          sync.Mutex
+		running     int
+		maxRunning  int
+		accountsRun int
+	)
+	results, err := pool.Run(context.Background(), func(ctx context.Context, _ string, _ *selvpcclient.ServiceClient) (interface{}, error) {
+		mu.Lock()
+		running++
+		accountsRun++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.Err() != nil {
+		t.Fatalf("unexpected error: %v", results.Err())
+	}
+	if accountsRun != 6 {
+		t.Fatalf("expected 6 processed accounts, but got %d", accountsRun)
+	}
+	if maxRunning > 2 {
+		t.Fatalf("expected at most 2 parallel calls, but got %d", maxRunning)
+	}
+}
+
+func TestClientPoolRunCanceled(t *testing.T) {
+	pool := selvpcclient.NewClientPool(1)
+	for _, account := range []string{"first", "second"} {
+		if err := pool.Add(account, &selvpcclient.ServiceClient{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := pool.Run(ctx, func(ctx context.Context, _ string, _ *selvpcclient.ServiceClient) (interface{}, error) {
+		cancel()
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, _ := results.Get("second")
+	if !errors.Is(result.Err, context.Canceled) {
+		t.Fatalf("expected context error for the second account, but got %v", result.Err)
+	}
+}
+
+func TestClientPoolRunPanic(t *testing.T) {
+	pool := selvpcclient.NewClientPool(2)
+	for _, account := range []string{"first", "panicked"} {
+		if err := pool.Add(account, &selvpcclient.ServiceClient{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	results, err := pool.Run(context.Background(), func(ctx context.Context, account string, client *selvpcclient.ServiceClient) (interface{}, error) {
+		if account == "panicked" {
+			panic("unexpected state")
+		}
+		return account, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, _ := results.Get("first")
+	if first.Err != nil || first.Value != "first" {
+		t.Errorf("unexpected result of the first account: %#v", first)
+	}
+	panicked, _ := results.Get("panicked")
+	if !errors.Is(panicked.Err, selvpcclient.ErrPoolPanic) {
+		t.Fatalf("expected ErrPoolPanic, but got %v", panicked.Err)
+	}
+	if !strings.Contains(panicked.Err.Error(), "unexpected state") {
+		t.Errorf("expected the panic value in the error, but got %q", panicked.Err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package testing
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+// testRegion implements the QueryEncoder.
+type testRegion struct {
+	name string
+}
+
+func (r testRegion) EncodeQueryParameter() (string, error) {
+	if r.name == "" {
+		return "", errors.New("empty region")
+	}
+	return strings.ToUpper(r.name), nil
+}
+
+// TestQueryPagination is embedded into the test options.
+type TestQueryPagination struct {
+	Limit  uint `param:"limit"`
+	Offset int  `param:"offset"`
+}
+
+func TestBuildQueryParameters(t *testing.T) {
+	enabled := false
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	opts := struct {
+		TestQueryPagination
+		IDs      []string                 `param:"id"`
+		Tags     []string                 `param:"tags,comma"`
+		Versions []selvpcclient.IPVersion `param:"type,comma"`
+		Since    time.Time                `param:"since"`
+		Day      time.Time                `param:"day,format=2006-01-02"`
+		Until    *time.Time               `param:"until,format=unix"`
+		Ratio    float64                  `param:"ratio"`
+		Enabled  *bool                    `param:"enabled"`
+		Region   testRegion               `param:"region"`
+		Empty    []int                    `param:"empty"`
+		Ignored  map[string]string
+	}{
+		TestQueryPagination: TestQueryPagination{Limit: 10},
+		IDs:                 []string{"1", "2"},
+		Tags:                []string{"a", "b"},
+		Versions:            []selvpcclient.IPVersion{selvpcclient.IPv4, selvpcclient.IPv6},
+		Since:               since,
+		Day:                 since,
+		Until:               &since,
+		Ratio:               0.25,
+		Enabled:             &enabled,
+		Region:              testRegion{name: "ru-1"},
+		Ignored:             map[string]string{"key": "value"},
+	}
+
+	actual, err := selvpcclient.BuildQueryParameters(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actualValues, err := url.ParseQuery(actual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := url.Values{
+		"limit":   {"10"},
+		"id":      {"1", "2"},
+		"tags":    {"a,b"},
+		"type":    {"ipv4,ipv6"},
+		"since":   {"2020-01-02T03:04:05Z"},
+		"day":     {"2020-01-02"},
+		"until":   {"1577934245"},
+		"ratio":   {"0.25"},
+		"enabled": {"false"},
+		"region":  {"RU-1"},
+	}
+	if actualValues.Encode() != expected.Encode() {
+		t.Fatalf("expected %s, but got %s", expected.Encode(), actual)
+	}
+}
+
+func TestBuildQueryParametersErrors(t *testing.T) {
+	unsupported := struct {
+		Filter map[string]string `param:"filter"`
+	}{
+		Filter: map[string]string{"key": "value"},
+	}
+	if _, err := selvpcclient.BuildQueryParameters(unsupported); err == nil {
+		t.Error("expected error for the unsupported map field")
+	}
+
+	failedEncoder := struct {
+		Regions []testRegion `param:"region"`
+	}{
+		Regions: []testRegion{{name: "ru-1"}, {}},
+	}
+	if _, err := selvpcclient.BuildQueryParameters(failedEncoder); err == nil {
+		t.Error("expected error from the QueryEncoder")
+	}
+
+	if _, err := selvpcclient.BuildQueryParameters(&failedEncoder); err == nil {
+		t.Error("expected error for the non-struct options")
+	}
+}
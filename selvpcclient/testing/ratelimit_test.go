@@ -0,0 +1,108 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestRateLimiter(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var waits []time.Duration
+	limiter := selvpcclient.NewRateLimiter(50, 2)
+	limiter.OnWait = func(_ *http.Request, wait time.Duration) {
+		waits = append(waits, wait)
+	}
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RateLimiter: limiter,
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// The first two requests use the burst, two others wait for 20ms each.
+	if len(waits) != 2 {
+		t.Fatalf("expected 2 waits, but got %d", len(waits))
+	}
+	if elapsed < 30*time.Millisecond {
+		t.Fatalf("expected requests to be limited, but they took %s", elapsed)
+	}
+}
+
+func TestRateLimiterMethodLimit(t *testing.T) {
+	limiter := selvpcclient.NewRateLimiter(0, 0)
+	limiter.SetMethodLimit(http.MethodPost, 1, 1)
+
+	ctx := context.Background()
+	getRequest, _ := http.NewRequest(http.MethodGet, "http://example.org", nil)
+	getRequest = getRequest.WithContext(ctx)
+	for i := 0; i < 10; i++ {
+		wait, err := limiter.Wait(getRequest)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if wait != 0 {
+			t.Fatalf("expected unlimited GET requests, but got %s wait", wait)
+		}
+	}
+
+	postCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	postRequest, _ := http.NewRequest(http.MethodPost, "http://example.org", nil)
+	postRequest = postRequest.WithContext(postCtx)
+	if _, err := limiter.Wait(postRequest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The second POST request needs to wait for a second that exceeds the
+	// context deadline.
+	start := time.Now()
+	if _, err := limiter.Wait(postRequest); err == nil {
+		t.Fatal("expected error when the wait exceeds the context deadline")
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Fatal("expected limiter to fail fast without waiting")
+	}
+}
+
+func TestRateLimiterZeroValue(t *testing.T) {
+	limiter := &selvpcclient.RateLimiter{}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://example.org", nil)
+	for i := 0; i < 10; i++ {
+		wait, err := limiter.Wait(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if wait != 0 {
+			t.Fatalf("expected no limit, but got %s wait", wait)
+		}
+	}
+
+	limiter.SetMethodLimit(http.MethodGet, 1, 1)
+	if _, err := limiter.Wait(request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Wait(request.WithContext(ctx)); err == nil {
+		t.Fatal("expected error when the wait exceeds the context deadline")
+	}
+}
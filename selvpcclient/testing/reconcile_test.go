@@ -0,0 +1,162 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+// reconcileEnv represents resources of the fake API that are used by the
+// CreateWithReconciliation tests.
+type reconcileEnv struct {
+	items     []selvpcclient.ReconcileItem
+	listCalls int
+	keys      []string
+}
+
+// list implements the ReconcileOpts's List.
+func (env *reconcileEnv) list(ctx context.Context) ([]selvpcclient.ReconcileItem, error) {
+	env.listCalls++
+
+	return append([]selvpcclient.ReconcileItem(nil), env.items...), nil
+}
+
+// create returns a function that creates the provided resources and fails
+// with the provided error.
+func (env *reconcileEnv) create(err error, created ...selvpcclient.ReconcileItem) func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+	return func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+		env.keys = append(env.keys, selvpcclient.IdempotencyKey(ctx))
+		env.items = append(env.items, created...)
+
+		return nil, err
+	}
+}
+
+func TestCreateWithReconciliation(t *testing.T) {
+	env := &reconcileEnv{items: []selvpcclient.ReconcileItem{{ID: "old", Key: "ru-1", Value: "old"}}}
+	opts := selvpcclient.ReconcileOpts{
+		List: env.list,
+		Create: env.create(&selvpcclient.APIError{StatusCode: http.StatusBadGateway},
+			selvpcclient.ReconcileItem{ID: "new-1", Key: "ru-1", Value: "new-1"},
+			selvpcclient.ReconcileItem{ID: "new-2", Key: "ru-1", Value: "new-2"},
+		),
+		Requested: map[string]int{"ru-1": 2},
+	}
+
+	created, _, err := selvpcclient.CreateWithReconciliation(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(created, []interface{}{"new-1", "new-2"}) {
+		t.Fatalf("expected the new resources, but got %v", created)
+	}
+	if len(env.keys) != 1 || env.keys[0] == "" {
+		t.Fatalf("expected a single create with the idempotency key, but got %v", env.keys)
+	}
+	if env.listCalls != 2 {
+		t.Fatalf("expected 2 list calls, but got %d", env.listCalls)
+	}
+}
+
+func TestCreateWithReconciliationExisting(t *testing.T) {
+	env := &reconcileEnv{}
+	opts := selvpcclient.ReconcileOpts{
+		List:      env.list,
+		Create:    env.create(nil),
+		Requested: map[string]int{"ru-1": 1},
+		Existing:  []selvpcclient.ReconcileItem{},
+	}
+
+	if _, _, err := selvpcclient.CreateWithReconciliation(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if env.listCalls != 0 {
+		t.Fatalf("expected no list calls for the successful creation, but got %d", env.listCalls)
+	}
+}
+
+func TestCreateWithReconciliationQuantityMismatch(t *testing.T) {
+	timeoutErr := &selvpcclient.APIError{StatusCode: http.StatusGatewayTimeout}
+	env := &reconcileEnv{}
+	opts := selvpcclient.ReconcileOpts{
+		List: env.list,
+		// Another client creates a resource in the same region at the same time.
+		Create: env.create(timeoutErr,
+			selvpcclient.ReconcileItem{ID: "new", Key: "ru-1", Value: "new"},
+			selvpcclient.ReconcileItem{ID: "other", Key: "ru-1", Value: "other"},
+		),
+		Requested: map[string]int{"ru-1": 1},
+	}
+
+	created, _, err := selvpcclient.CreateWithReconciliation(context.Background(), opts)
+	if !errors.Is(err, timeoutErr) {
+		t.Fatalf("expected %v, but got %v", timeoutErr, err)
+	}
+	if created != nil {
+		t.Fatalf("expected no reconciled resources, but got %v", created)
+	}
+	if len(env.keys) != 1 {
+		t.Fatalf("expected a single create, but got %d", len(env.keys))
+	}
+}
+
+func TestCreateWithReconciliationRepeat(t *testing.T) {
+	env := &reconcileEnv{items: []selvpcclient.ReconcileItem{{ID: "old", Key: "ru-1"}}}
+	calls := 0
+	create := func(ctx context.Context) (*selvpcclient.ResponseResult, error) {
+		calls++
+		env.keys = append(env.keys, selvpcclient.IdempotencyKey(ctx))
+		if calls == 1 {
+			return nil, context.DeadlineExceeded
+		}
+
+		return nil, nil
+	}
+	opts := selvpcclient.ReconcileOpts{
+		List:      env.list,
+		Create:    create,
+		Requested: map[string]int{"ru-1": 1},
+	}
+
+	created, _, err := selvpcclient.CreateWithReconciliation(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != nil {
+		t.Fatalf("expected no reconciled resources, but got %v", created)
+	}
+	if len(env.keys) != 2 || env.keys[0] != env.keys[1] {
+		t.Fatalf("expected the repeated create with the same idempotency key, but got %v", env.keys)
+	}
+}
+
+func TestCreateWithReconciliationNotSent(t *testing.T) {
+	refusedErr := &url.Error{
+		Op:  http.MethodPost,
+		URL: "http://127.0.0.1:1",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+	}
+	env := &reconcileEnv{}
+	opts := selvpcclient.ReconcileOpts{
+		List:      env.list,
+		Create:    env.create(refusedErr),
+		Requested: map[string]int{"ru-1": 1},
+	}
+
+	_, _, err := selvpcclient.CreateWithReconciliation(context.Background(), opts)
+	if !errors.Is(err, refusedErr) {
+		t.Fatalf("expected %v, but got %v", refusedErr, err)
+	}
+	if env.listCalls != 1 {
+		t.Fatalf("expected no reconciliation list, but got %d list calls", env.listCalls)
+	}
+	if len(env.keys) != 1 {
+		t.Fatalf("expected a single create, but got %d", len(env.keys))
+	}
+}
@@ -0,0 +1,171 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+// newTestRetryPolicy returns a retry policy with short delays.
+func newTestRetryPolicy() *selvpcclient.RetryPolicy {
+	return &selvpcclient.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestDoRequestRetry(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	attempts := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "uuid"}`)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RetryPolicy: newTestRetryPolicy(),
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Err != nil {
+		t.Fatalf("unexpected error in the response result: %v", response.Err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, but got %d", attempts)
+	}
+}
+
+func TestDoRequestRetryExhausted(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	attempts := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RetryPolicy: newTestRetryPolicy(),
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodDelete, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected %d status, but got %d", http.StatusBadGateway, response.StatusCode)
+	}
+	if response.Err == nil {
+		t.Fatal("expected error in the response result")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, but got %d", attempts)
+	}
+}
+
+func TestDoRequestRetryNonIdempotent(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	requestBody := `{"id": "uuid"}`
+	attempts := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unable to read the request body: %v", err)
+		}
+		if string(b) != requestBody {
+			t.Errorf("expected %s body on attempt %d, but got %s", requestBody, attempts, b)
+		}
+		if attempts < 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	policy := newTestRetryPolicy()
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RetryPolicy: policy,
+	}
+
+	// POST requests aren't retried by default.
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodPost, testEnv.Server.URL, bytes.NewBufferString(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected %d status, but got %d", http.StatusTooManyRequests, response.StatusCode)
+	}
+
+	attempts = 0
+	policy.RetryNonIdempotent = true
+	response, err = client.DoRequest(ctx, http.MethodPost, testEnv.Server.URL, bytes.NewBufferString(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("expected %d status, but got %d", http.StatusCreated, response.StatusCode)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, but got %d", attempts)
+	}
+}
+
+func TestDoRequestRetryAfter(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	attempts := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		RetryPolicy: newTestRetryPolicy(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error while waiting for Retry-After, but got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, but got %d", attempts)
+	}
+}
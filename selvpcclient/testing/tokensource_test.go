@@ -0,0 +1,138 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+func TestDoRequestTokenSourceRefresh(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	requestBody := `{"id": "uuid"}`
+	var tokens []string
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("X-token"))
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unable to read the request body: %v", err)
+		}
+		if string(b) != requestBody {
+			t.Errorf("expected %s request body, but got %s", requestBody, b)
+		}
+		if r.Header.Get("X-token") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	sourceCalls := 0
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   testEnv.Server.URL,
+		TokenID:    "ignored",
+		TokenSource: selvpcclient.TokenSourceFunc(func(_ context.Context) (string, error) {
+			sourceCalls++
+			if sourceCalls == 1 {
+				return "stale", nil
+			}
+			return "fresh", nil
+		}),
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodPost, testEnv.Server.URL, bytes.NewBufferString(requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("expected %d status, but got %d", http.StatusCreated, response.StatusCode)
+	}
+	if len(tokens) != 2 || tokens[0] != "stale" || tokens[1] != "fresh" {
+		t.Fatalf("expected requests with stale and fresh tokens, but got %v", tokens)
+	}
+}
+
+func TestDoRequestStaticTokenSourceUnauthorized(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	requests := 0
+	testEnv.Mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	client := &selvpcclient.ServiceClient{
+		HTTPClient:  &http.Client{},
+		Endpoint:    testEnv.Server.URL,
+		TokenSource: selvpcclient.StaticTokenSource("token"),
+	}
+
+	ctx := context.Background()
+	response, err := client.DoRequest(ctx, http.MethodGet, testEnv.Server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !selvpcclient.IsUnauthorized(response.Err) {
+		t.Fatalf("expected unauthorized error, but got %v", response.Err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected request not to be replayed with the same token, but got %d requests", requests)
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "selvpcclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := selvpcclient.NewFileTokenSource(path)
+	ctx := context.Background()
+	token, err := source.Token(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "first" {
+		t.Fatalf("expected first token, but got %s", token)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("second-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	token, err = source.Token(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "second-token" {
+		t.Fatalf("expected second-token token after the file change, but got %s", token)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("  "), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Token(ctx); err == nil {
+		t.Fatal("expected error for the empty token file")
+	}
+}
@@ -0,0 +1,120 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/testutils"
+)
+
+// fakeTracer records started and finished spans.
+type fakeTracer struct {
+	operations []selvpcclient.Operation
+	spans      []*fakeSpan
+}
+
+// fakeSpan records the result of a single span.
+type fakeSpan struct {
+	ended  bool
+	result *selvpcclient.ResponseResult
+	err    error
+}
+
+func (tracer *fakeTracer) StartSpan(operation selvpcclient.Operation, request *http.Request) (*http.Request, selvpcclient.Span) {
+	request.Header.Set("Traceparent", "00-trace-span-01")
+	tracer.operations = append(tracer.operations, operation)
+	span := &fakeSpan{}
+	tracer.spans = append(tracer.spans, span)
+
+	return request, span
+}
+
+func (span *fakeSpan) End(result *selvpcclient.ResponseResult, err error) {
+	span.ended = true
+	span.result = result
+	span.err = err
+}
+
+func TestDoRequestTracer(t *testing.T) {
+	testEnv := testutils.SetupTestEnv()
+	defer testEnv.TearDownTestEnv()
+
+	attempts := 0
+	testEnv.Mux.HandleFunc("/resell/v2/floatingips/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("Traceparent") != "00-trace-span-01" {
+			t.Errorf("expected propagated trace context, but got %q", r.Header.Get("Traceparent"))
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tracer := &fakeTracer{}
+	endpoint := testEnv.Server.URL + "/resell/v2"
+	client := &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   endpoint,
+		Tracer:     tracer,
+		RetryPolicy: &selvpcclient.RetryPolicy{
+			MaxAttempts: 2,
+			MinBackoff:  time.Millisecond,
+		},
+	}
+
+	ctx := context.Background()
+	_, err := client.DoRequest(ctx, http.MethodDelete, endpoint+"/floatingips/5232d5f3", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected a single span, but got %d", len(tracer.spans))
+	}
+	expectedOperation := selvpcclient.Operation{Resource: "floatingips", Name: selvpcclient.OperationDelete}
+	if tracer.operations[0] != expectedOperation {
+		t.Errorf("expected %v operation, but got %v", expectedOperation, tracer.operations[0])
+	}
+	span := tracer.spans[0]
+	if !span.ended {
+		t.Fatal("expected span to be ended")
+	}
+	if span.result.StatusCode != http.StatusNoContent {
+		t.Errorf("expected %d status in the span, but got %d", http.StatusNoContent, span.result.StatusCode)
+	}
+	if span.result.Attempts != 2 {
+		t.Errorf("expected 2 attempts in the span, but got %d", span.result.Attempts)
+	}
+}
+
+func TestOperationFromRequest(t *testing.T) {
+	endpoint := "https://api.selectel.ru/vpc/resell/v2"
+	testCases := []struct {
+		method   string
+		url      string
+		expected selvpcclient.Operation
+	}{
+		{http.MethodGet, endpoint + "/projects", selvpcclient.Operation{Resource: "projects", Name: "list"}},
+		{http.MethodGet, endpoint + "/projects/49338ac0", selvpcclient.Operation{Resource: "projects", Name: "get"}},
+		{http.MethodGet, endpoint + "/floatingips?detailed=true", selvpcclient.Operation{Resource: "floatingips", Name: "list"}},
+		{http.MethodPost, endpoint + "/subnets/projects/49338ac0", selvpcclient.Operation{Resource: "subnets", Name: "create"}},
+		{http.MethodPatch, endpoint + "/quotas/projects/49338ac0", selvpcclient.Operation{Resource: "quotas", Name: "update"}},
+		{http.MethodDelete, endpoint + "/keypairs/key/users/82a026cae2104e92b999dbe00cdb9435", selvpcclient.Operation{Resource: "keypairs", Name: "delete"}},
+	}
+
+	for _, testCase := range testCases {
+		request, err := http.NewRequest(testCase.method, testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := selvpcclient.OperationFromRequest(endpoint, request)
+		if actual != testCase.expected {
+			t.Errorf("expected %v operation for %s %s, but got %v", testCase.expected, testCase.method, testCase.url, actual)
+		}
+	}
+}
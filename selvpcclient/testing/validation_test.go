@@ -0,0 +1,62 @@
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+func TestValidationErrorMerge(t *testing.T) {
+	var nested selvpcclient.ValidationError
+	nested.Add("user_id", "is required")
+
+	var validationErr selvpcclient.ValidationError
+	validationErr.Add("roles", "must contain at least one role")
+	validationErr.Merge("roles[1]", nested.Err())
+	validationErr.Merge("roles[2]", errors.New("is broken"))
+	validationErr.Merge("roles[3]", nil)
+
+	expected := []selvpcclient.FieldError{
+		{Field: "roles", Message: "must contain at least one role"},
+		{Field: "roles[1].user_id", Message: "is required"},
+		{Field: "roles[2]", Message: "is broken"},
+	}
+	if len(validationErr.Errors) != len(expected) {
+		t.Fatalf("expected %v, but got %v", expected, validationErr.Errors)
+	}
+	for i := range expected {
+		if validationErr.Errors[i] != expected[i] {
+			t.Errorf("expected %v, but got %v", expected[i], validationErr.Errors[i])
+		}
+	}
+	if !selvpcclient.IsValidationError(validationErr.Err()) {
+		t.Fatal("expected ValidationError from the Err method")
+	}
+
+	var empty selvpcclient.ValidationError
+	if empty.Err() != nil {
+		t.Fatalf("expected no error, but got %v", empty.Err())
+	}
+}
+
+func TestValidatePrefixLength(t *testing.T) {
+	testCases := []struct {
+		ipVersion    selvpcclient.IPVersion
+		prefixLength int
+		valid        bool
+	}{
+		{selvpcclient.IPv4, 29, true},
+		{selvpcclient.IPv4, 33, false},
+		{selvpcclient.IPv6, 64, true},
+		{selvpcclient.IPv6, 0, false},
+		{"ipv5", 24, false},
+	}
+
+	for _, testCase := range testCases {
+		err := selvpcclient.ValidatePrefixLength(testCase.ipVersion, testCase.prefixLength)
+		if (err == nil) != testCase.valid {
+			t.Errorf("%s/%d: expected valid %t, but got %v", testCase.ipVersion, testCase.prefixLength, testCase.valid, err)
+		}
+	}
+}
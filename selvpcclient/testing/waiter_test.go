@@ -0,0 +1,228 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+)
+
+// statusSequence returns a StatusFunc that returns provided statuses one by
+// one and repeats the last one.
+func statusSequence(calls *int, statuses ...string) selvpcclient.StatusFunc {
+	return func(ctx context.Context) (string, error) {
+		i := *calls
+		*calls++
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+
+		return statuses[i], nil
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	calls := 0
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	statusFunc := statusSequence(&calls, "DOWN", "DOWN", "ACTIVE")
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "floatingips", "id", statusFunc, []string{"ACTIVE"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForStatusFailed(t *testing.T) {
+	calls := 0
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	statusFunc := statusSequence(&calls, "DOWN", "ERROR", "ACTIVE")
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "floatingips", "id", statusFunc, []string{"ACTIVE"}, []string{"ERROR"})
+
+	var statusErr *selvpcclient.UnexpectedStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected UnexpectedStatusError, but got %v", err)
+	}
+	if statusErr.Status != "ERROR" || statusErr.Resource != "floatingips" || statusErr.ID != "id" {
+		t.Fatalf("unexpected error fields: %#v", statusErr)
+	}
+	expected := "selvpcclient: floatingips id reached the ERROR status while waiting for ACTIVE"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, but got %q", expected, err.Error())
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForStatusDeleted(t *testing.T) {
+	notFound := func(ctx context.Context) (string, error) {
+		return "", &selvpcclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "subnets", "id", notFound, []string{selvpcclient.StatusDeleted}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = selvpcclient.WaitForStatus(context.Background(), opts, "subnets", "id", notFound, []string{"ACTIVE"}, nil)
+	var statusErr *selvpcclient.UnexpectedStatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != selvpcclient.StatusDeleted {
+		t.Fatalf("expected UnexpectedStatusError with the DELETED status, but got %v", err)
+	}
+}
+
+func TestWaitForStatusTimeout(t *testing.T) {
+	calls := 0
+	opts := selvpcclient.WaitOpts{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	}
+	statusFunc := statusSequence(&calls, "DOWN")
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "licenses", "id", statusFunc, []string{"ACTIVE"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "last status is DOWN") {
+		t.Fatalf("expected the last status in the error, but got %q", err.Error())
+	}
+	if calls < 2 {
+		t.Fatalf("expected several status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForStatusFuncError(t *testing.T) {
+	expectedErr := errors.New("unauthorized")
+	statusFunc := func(ctx context.Context) (string, error) {
+		return "", expectedErr
+	}
+
+	err := selvpcclient.WaitForStatus(context.Background(), selvpcclient.WaitOpts{}, "subnets", "id", statusFunc, []string{"ACTIVE"}, nil)
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, but got %v", expectedErr, err)
+	}
+}
+
+func TestWaitForStatusTransientError(t *testing.T) {
+	calls := 0
+	statusFunc := func(ctx context.Context) (string, error) {
+		calls++
+		switch calls {
+		case 1:
+			return "", &selvpcclient.APIError{StatusCode: http.StatusServiceUnavailable}
+		case 2:
+			return "", &selvpcclient.APIError{StatusCode: http.StatusTooManyRequests}
+		}
+
+		return "ACTIVE", nil
+	}
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "subnets", "id", statusFunc, []string{"ACTIVE"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForStatusTransientErrorTimeout(t *testing.T) {
+	calls := 0
+	statusFunc := func(ctx context.Context) (string, error) {
+		calls++
+		return "", &selvpcclient.APIError{StatusCode: http.StatusBadGateway}
+	}
+	opts := selvpcclient.WaitOpts{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	}
+
+	err := selvpcclient.WaitForStatus(context.Background(), opts, "subnets", "id", statusFunc, []string{"ACTIVE"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, but got %v", err)
+	}
+	if calls < 2 {
+		t.Fatalf("expected several status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForStatusInvalidArguments(t *testing.T) {
+	calls := 0
+	statusFunc := statusSequence(&calls, "ACTIVE")
+
+	if err := selvpcclient.WaitForStatus(context.Background(), selvpcclient.WaitOpts{}, "subnets", "id", statusFunc, nil, nil); err == nil {
+		t.Fatal("expected an error without target statuses")
+	}
+	if err := selvpcclient.WaitForStatus(context.Background(), selvpcclient.WaitOpts{}, "subnets", "id", nil, []string{"ACTIVE"}, nil); err == nil {
+		t.Fatal("expected an error without the status function")
+	}
+	if calls != 0 {
+		t.Fatalf("expected no status checks, but got %d", calls)
+	}
+}
+
+func TestWaitForResource(t *testing.T) {
+	calls := 0
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	getFunc := func(ctx context.Context) (interface{}, string, error) {
+		calls++
+		if calls < 3 {
+			return calls, "DOWN", nil
+		}
+
+		return calls, "ACTIVE", nil
+	}
+
+	resource, err := selvpcclient.WaitForResource(context.Background(), opts, "floatingips", "id", getFunc, []string{"ACTIVE"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resource != 3 {
+		t.Fatalf("expected the resource of the last check, but got %v", resource)
+	}
+}
+
+func TestWaitForResourceFailed(t *testing.T) {
+	opts := selvpcclient.WaitOpts{Interval: time.Millisecond}
+	getFunc := func(ctx context.Context) (interface{}, string, error) {
+		return "failed", "ERROR", nil
+	}
+
+	resource, err := selvpcclient.WaitForResource(context.Background(), opts, "floatingips", "id", getFunc, []string{"ACTIVE"}, []string{"ERROR"})
+
+	var statusErr *selvpcclient.UnexpectedStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected UnexpectedStatusError, but got %v", err)
+	}
+	if resource != "failed" {
+		t.Fatalf("expected the failed resource, but got %v", resource)
+	}
+}
+
+func TestWaitForResourceNoResource(t *testing.T) {
+	getFunc := func(ctx context.Context) (interface{}, string, error) {
+		return nil, "", nil
+	}
+
+	resource, err := selvpcclient.WaitForResource(context.Background(), selvpcclient.WaitOpts{}, "floatingips", "id", getFunc, []string{"ACTIVE"}, nil)
+	if !errors.Is(err, selvpcclient.ErrWaitNoResource) {
+		t.Fatalf("expected ErrWaitNoResource, but got %v", err)
+	}
+	if resource != nil {
+		t.Fatalf("expected no resource, but got %v", resource)
+	}
+
+	if _, err := selvpcclient.WaitForResource(context.Background(), selvpcclient.WaitOpts{}, "floatingips", "id", nil, []string{"ACTIVE"}, nil); err == nil {
+		t.Fatal("expected an error without the get function")
+	}
+}
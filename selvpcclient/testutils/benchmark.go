@@ -0,0 +1,51 @@
+package testutils
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+// ReportPeakHeap runs the function once outside of the benchmark timer and
+// reports the peak heap growth during the run as the "peak-B/op" metric.
+// The garbage collector runs very often during the run, so the peak is close
+// to the size of the objects that are alive at the same time.
+func ReportPeakHeap(b *testing.B, fn func()) {
+	b.StopTimer()
+	defer b.StartTimer()
+	defer debug.SetGCPercent(debug.SetGCPercent(1))
+
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	base := stats.HeapAlloc
+
+	var peak uint64
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		var stats runtime.MemStats
+		for {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > peak {
+				peak = stats.HeapAlloc
+			}
+			select {
+			case <-done:
+				return
+			default:
+				runtime.Gosched()
+			}
+		}
+	}()
+	fn()
+	close(done)
+	<-sampled
+
+	growth := uint64(0)
+	if peak > base {
+		growth = peak - base
+	}
+	b.ReportMetric(float64(growth), "peak-B/op")
+}
@@ -0,0 +1,141 @@
+package selvpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errEmptyToken = errors.New("token source returned empty token")
+
+// TokenSource provides authentication tokens for the ServiceClient.
+// It's consulted before every request and once more after the 401 response
+// so a rotated token can be picked up without recreating the client.
+type TokenSource interface {
+	// Token returns the current authentication token.
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is an adapter to use ordinary functions as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// staticTokenSource always returns the same token.
+type staticTokenSource string
+
+// Token returns the static token.
+func (s staticTokenSource) Token(_ context.Context) (string, error) {
+	return string(s), nil
+}
+
+// StaticTokenSource returns a TokenSource that always returns the provided
+// token.
+func StaticTokenSource(tokenID string) TokenSource {
+	return staticTokenSource(tokenID)
+}
+
+// FileTokenSource reads the token from a file and re-reads it every time the
+// file is changed. Leading and trailing whitespaces are trimmed.
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource returns a reference to the FileTokenSource that reads
+// the token from the provided path.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns the token from the file. The file is read again only if its
+// modification time or size were changed since the previous read.
+func (s *FileTokenSource) Token(_ context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("unable to stat the token file: %w", err)
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the token file: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("%w from the %s file", errEmptyToken, s.path)
+	}
+	s.token, s.modTime, s.size = token, info.ModTime(), info.Size()
+
+	return s.token, nil
+}
+
+// TokenSourceMiddleware returns a middleware that sets the X-token
+// authentication header with the token from the provided source.
+// If the server responds with 401, the source is asked for a token once more
+// and the request is replayed if the token was changed.
+func TokenSourceMiddleware(source TokenSource) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			ctx := request.Context()
+			tokenID, err := sourceToken(ctx, source)
+			if err != nil {
+				return nil, err
+			}
+			request.Header.Set("X-token", tokenID)
+
+			result, err := next(request)
+			if err != nil || result.StatusCode != http.StatusUnauthorized {
+				return result, err
+			}
+
+			freshTokenID, err := sourceToken(ctx, source)
+			if err != nil || freshTokenID == tokenID {
+				return result, nil
+			}
+
+			replay := request.Clone(ctx)
+			if request.GetBody != nil {
+				body, err := request.GetBody()
+				if err != nil {
+					return result, nil
+				}
+				replay.Body = body
+			}
+			replay.Header.Set("X-token", freshTokenID)
+
+			return next(replay)
+		}
+	}
+}
+
+// sourceToken returns a non-empty token from the provided source.
+func sourceToken(ctx context.Context, source TokenSource) (string, error) {
+	tokenID, err := source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("selvpcclient: unable to get the token: %w", err)
+	}
+	if tokenID == "" {
+		return "", fmt.Errorf("selvpcclient: %w", errEmptyToken)
+	}
+
+	return tokenID, nil
+}
@@ -0,0 +1,112 @@
+package selvpcclient
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// OperationList represents a request of the resources list.
+	OperationList = "list"
+
+	// OperationGet represents a request of a single resource.
+	OperationGet = "get"
+
+	// OperationCreate represents a creation request.
+	OperationCreate = "create"
+
+	// OperationUpdate represents an update request.
+	OperationUpdate = "update"
+
+	// OperationDelete represents a deletion request.
+	OperationDelete = "delete"
+)
+
+// Operation describes an API call performed by the ServiceClient.
+type Operation struct {
+	// Resource contains the name of the requested resource like "projects"
+	// or "floatingips".
+	Resource string
+
+	// Name contains the kind of the call like "list" or "create".
+	Name string
+}
+
+// String returns the operation in the "resource.name" form.
+func (operation Operation) String() string {
+	return operation.Resource + "." + operation.Name
+}
+
+// Tracer starts spans for the ServiceClient's requests.
+// Implementations can inject trace context headers into the provided request.
+// See the tracing/otelselvpc package for the OpenTelemetry adapter.
+type Tracer interface {
+	// StartSpan starts a span for the request. The request is sent with the
+	// context of the returned request so it should be derived from the
+	// provided one.
+	StartSpan(operation Operation, request *http.Request) (*http.Request, Span)
+}
+
+// Span represents a single traced request.
+type Span interface {
+	// End finishes the span with the request result. The result is nil if
+	// the request failed with the provided error.
+	End(result *ResponseResult, err error)
+}
+
+// TracingMiddleware returns a middleware that starts a span with the
+// provided tracer for every request.
+func TracingMiddleware(tracer Tracer, endpoint string) Middleware {
+	return func(next Handler) Handler {
+		return func(request *http.Request) (*ResponseResult, error) {
+			request, span := tracer.StartSpan(OperationFromRequest(endpoint, request), request)
+			result, err := next(request)
+			span.End(result, err)
+
+			return result, err
+		}
+	}
+}
+
+// OperationFromRequest returns the operation of the request to the provided
+// API endpoint. Resource is the first path element after the endpoint and the
+// name is derived from the request method.
+func OperationFromRequest(endpoint string, request *http.Request) Operation {
+	path := request.URL.Path
+	if endpointURL, err := request.URL.Parse(endpoint); err == nil {
+		path = strings.TrimPrefix(path, endpointURL.Path)
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var name string
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+		name = OperationGet
+		if len(segments) == 1 {
+			name = OperationList
+		}
+	case http.MethodPost:
+		name = OperationCreate
+	case http.MethodPut, http.MethodPatch:
+		name = OperationUpdate
+	case http.MethodDelete:
+		name = OperationDelete
+	default:
+		name = strings.ToLower(request.Method)
+	}
+
+	return Operation{
+		Resource: segments[0],
+		Name:     name,
+	}
+}
+
+// tracingMiddlewares returns the TracingMiddleware if the ServiceClient's
+// Tracer is set.
+func (client *ServiceClient) tracingMiddlewares() []Middleware {
+	if client.Tracer == nil {
+		return nil
+	}
+
+	return []Middleware{TracingMiddleware(client.Tracer, client.Endpoint)}
+}
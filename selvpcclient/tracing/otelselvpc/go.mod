@@ -0,0 +1,12 @@
+module github.com/selectel/go-selvpcclient/selvpcclient/tracing/otelselvpc
+
+go 1.16
+
+require (
+	github.com/selectel/go-selvpcclient v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.7.0
+	go.opentelemetry.io/otel/sdk v1.7.0
+	go.opentelemetry.io/otel/trace v1.7.0
+)
+
+replace github.com/selectel/go-selvpcclient => ../../..
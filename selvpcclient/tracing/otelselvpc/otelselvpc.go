@@ -0,0 +1,127 @@
+/*
+Package otelselvpc provides the OpenTelemetry adapter for the selvpcclient
+Tracer interface.
+
+It's a separate module, so the core library doesn't depend on OpenTelemetry.
+
+Example of tracing all requests of the Resell client
+
+  resellClient := resell.NewV2ResellClient(token)
+  resellClient.Tracer = otelselvpc.NewTracer()
+*/
+package otelselvpc
+
+import (
+	"net/http"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// instrumentationName contains the name of the instrumentation library.
+	instrumentationName = "github.com/selectel/go-selvpcclient/selvpcclient/tracing/otelselvpc"
+
+	// spanNamePrefix contains the prefix of all span names.
+	spanNamePrefix = "selvpcclient."
+)
+
+// Attribute keys that are set for every span.
+const (
+	ResourceKey   = attribute.Key("selvpcclient.resource")
+	OperationKey  = attribute.Key("selvpcclient.operation")
+	RetryCountKey = attribute.Key("selvpcclient.retry_count")
+	RequestIDKey  = attribute.Key("selvpcclient.request_id")
+
+	httpMethodKey     = attribute.Key("http.method")
+	httpURLKey        = attribute.Key("http.url")
+	httpStatusCodeKey = attribute.Key("http.status_code")
+)
+
+// Option configures the Tracer.
+type Option func(*Tracer)
+
+// WithTracerProvider sets the provider that is used to create a tracer.
+// The global provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.tracer = provider.Tracer(instrumentationName)
+	}
+}
+
+// WithPropagators sets the propagators that inject trace context headers.
+// The global propagators are used by default.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		t.propagators = propagators
+	}
+}
+
+// Tracer implements the selvpcclient.Tracer with the OpenTelemetry API.
+type Tracer struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+}
+
+// NewTracer returns a reference to the Tracer configured with the provided
+// options.
+func NewTracer(opts ...Option) *Tracer {
+	t := &Tracer{
+		tracer:      otel.GetTracerProvider().Tracer(instrumentationName),
+		propagators: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// StartSpan starts a client span for the request and injects the trace
+// context headers.
+func (t *Tracer) StartSpan(operation selvpcclient.Operation, request *http.Request) (*http.Request, selvpcclient.Span) {
+	ctx, span := t.tracer.Start(request.Context(), spanNamePrefix+operation.String(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			ResourceKey.String(operation.Resource),
+			OperationKey.String(operation.Name),
+			httpMethodKey.String(request.Method),
+			httpURLKey.String(request.URL.String()),
+		),
+	)
+	t.propagators.Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	return request.WithContext(ctx), &Span{span: span}
+}
+
+// Span implements the selvpcclient.Span with the OpenTelemetry API.
+type Span struct {
+	span trace.Span
+}
+
+// End sets the response attributes and finishes the span.
+func (s *Span) End(result *selvpcclient.ResponseResult, err error) {
+	defer s.span.End()
+
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	s.span.SetAttributes(
+		httpStatusCodeKey.Int(result.StatusCode),
+		RetryCountKey.Int(result.Attempts-1),
+	)
+	if result.RequestID != "" {
+		s.span.SetAttributes(RequestIDKey.String(result.RequestID))
+	}
+	if result.Err != nil {
+		s.span.RecordError(result.Err)
+		s.span.SetStatus(codes.Error, http.StatusText(result.StatusCode))
+	}
+}
@@ -0,0 +1,202 @@
+package testing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/selectel/go-selvpcclient/selvpcclient"
+	"github.com/selectel/go-selvpcclient/selvpcclient/tracing/otelselvpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newRecordedClient returns the ServiceClient for the test server that
+// records its spans with the returned SpanRecorder.
+func newRecordedClient(serverURL string) (*selvpcclient.ServiceClient, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	return &selvpcclient.ServiceClient{
+		HTTPClient: &http.Client{},
+		Endpoint:   serverURL + "/resell/v2",
+		Tracer: otelselvpc.NewTracer(
+			otelselvpc.WithTracerProvider(provider),
+			otelselvpc.WithPropagators(propagation.TraceContext{}),
+		),
+	}, recorder
+}
+
+// spanAttributes returns attributes of the span by their keys.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+
+	return attributes
+}
+
+func TestTracerPropagatesTraceContext(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	client, recorder := newRecordedClient(server.URL)
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	_, err := client.DoRequest(ctx, http.MethodGet, server.URL+"/resell/v2/floatingips", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, but got %d", len(spans))
+	}
+	span := spans[0]
+	if span.SpanContext().TraceID() != traceID || span.Parent().SpanID() != spanID {
+		t.Fatalf("expected the child span of the context span, but got %v", span.SpanContext())
+	}
+
+	// The traceparent header contains the span of the request.
+	expected := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + span.SpanContext().SpanID().String() + "-01"
+	if traceparent != expected {
+		t.Fatalf("expected %s traceparent header, but got %q", expected, traceparent)
+	}
+}
+
+func TestTracerSpanAttributes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-id")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, recorder := newRecordedClient(server.URL)
+	_, err := client.DoRequest(context.Background(), http.MethodGet, server.URL+"/resell/v2/floatingips", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, but got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "selvpcclient.floatingips.list" {
+		t.Errorf("expected selvpcclient.floatingips.list span, but got %s", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected client span, but got %s", span.SpanKind())
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("expected unset span status, but got %v", span.Status())
+	}
+
+	attributes := spanAttributes(span)
+	if value := attributes[otelselvpc.ResourceKey].AsString(); value != "floatingips" {
+		t.Errorf("expected floatingips resource, but got %q", value)
+	}
+	if value := attributes[otelselvpc.OperationKey].AsString(); value != selvpcclient.OperationList {
+		t.Errorf("expected %s operation, but got %q", selvpcclient.OperationList, value)
+	}
+	if value := attributes["http.status_code"].AsInt64(); value != http.StatusOK {
+		t.Errorf("expected 200 HTTP status, but got %d", value)
+	}
+	retryCount, ok := attributes[otelselvpc.RetryCountKey]
+	if !ok || retryCount.AsInt64() != 0 {
+		t.Errorf("expected 0 retry count, but got %v", retryCount.Emit())
+	}
+	if value := attributes[otelselvpc.RequestIDKey].AsString(); value != "request-id" {
+		t.Errorf("expected request-id request ID, but got %q", value)
+	}
+}
+
+func TestTracerSpanRetryCount(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, recorder := newRecordedClient(server.URL)
+	client.RetryPolicy = &selvpcclient.RetryPolicy{
+		MaxAttempts:      3,
+		MinBackoff:       time.Millisecond,
+		MaxBackoff:       time.Millisecond,
+		RetryStatusCodes: []int{http.StatusServiceUnavailable},
+	}
+	_, err := client.DoRequest(context.Background(), http.MethodGet, server.URL+"/resell/v2/floatingips/id", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span for the retried request, but got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "selvpcclient.floatingips.get" {
+		t.Errorf("expected selvpcclient.floatingips.get span, but got %s", span.Name())
+	}
+
+	attributes := spanAttributes(span)
+	if value := attributes[otelselvpc.OperationKey].AsString(); value != selvpcclient.OperationGet {
+		t.Errorf("expected %s operation, but got %q", selvpcclient.OperationGet, value)
+	}
+	if value := attributes["http.status_code"].AsInt64(); value != http.StatusCreated {
+		t.Errorf("expected 201 HTTP status, but got %d", value)
+	}
+	if value := attributes[otelselvpc.RetryCountKey].AsInt64(); value != 2 {
+		t.Errorf("expected 2 retry count, but got %d", value)
+	}
+}
+
+func TestTracerSpanErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, recorder := newRecordedClient(server.URL)
+	_, err := client.DoRequest(context.Background(), http.MethodDelete, server.URL+"/resell/v2/floatingips/id", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, but got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "selvpcclient.floatingips.delete" {
+		t.Errorf("expected selvpcclient.floatingips.delete span, but got %s", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error span status, but got %v", span.Status())
+	}
+	if value := spanAttributes(span)["http.status_code"].AsInt64(); value != http.StatusNotFound {
+		t.Errorf("expected 404 HTTP status, but got %d", value)
+	}
+}
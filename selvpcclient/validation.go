@@ -0,0 +1,115 @@
+package selvpcclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single invalid field of the request options.
+type FieldError struct {
+	// Field contains the JSON path of the field like "floatingips[0].quantity".
+	Field string
+
+	// Message contains the description of the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError contains all invalid fields of the request options.
+// It's returned by the Validate methods of the options structs before the
+// request is sent and can be retrieved with errors.As.
+type ValidationError struct {
+	// Errors contains the invalid fields in the order they were checked.
+	Errors []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Error())
+	}
+
+	return "selvpcclient: invalid options: " + strings.Join(messages, "; ")
+}
+
+// Add adds the invalid field with the provided message.
+func (e *ValidationError) Add(field, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: message})
+}
+
+// Addf adds the invalid field with the formatted message.
+func (e *ValidationError) Addf(field, format string, args ...interface{}) {
+	e.Add(field, fmt.Sprintf(format, args...))
+}
+
+// Merge adds invalid fields of the nested options with the provided prefix.
+// Other errors are added as the error of the prefix field.
+func (e *ValidationError) Merge(prefix string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested *ValidationError
+	if !errors.As(err, &nested) {
+		e.Add(prefix, err.Error())
+		return
+	}
+	for _, fieldErr := range nested.Errors {
+		field := fieldErr.Field
+		switch {
+		case prefix == "":
+		case strings.HasPrefix(field, "["):
+			field = prefix + field
+		default:
+			field = prefix + "." + field
+		}
+		e.Add(field, fieldErr.Message)
+	}
+}
+
+// Err returns the ValidationError if it contains invalid fields or nil
+// otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// IsValidationError checks if provided error is the ValidationError.
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+
+	return errors.As(err, &validationErr)
+}
+
+// ValidatePrefixLength checks if the prefix length is valid for the IP
+// version.
+func ValidatePrefixLength(ipVersion IPVersion, prefixLength int) error {
+	maxLength := 0
+	switch ipVersion {
+	case IPv4:
+		maxLength = 32
+	case IPv6:
+		maxLength = 128
+	default:
+		return fmt.Errorf("unknown IP version %q", ipVersion)
+	}
+	if prefixLength < 1 || prefixLength > maxLength {
+		return fmt.Errorf("must be between 1 and %d for %s", maxLength, ipVersion)
+	}
+
+	return nil
+}
+
+// IsValid checks if the IP version is known.
+func (ipVersion IPVersion) IsValid() bool {
+	return ipVersion == IPv4 || ipVersion == IPv6
+}
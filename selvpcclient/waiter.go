@@ -0,0 +1,218 @@
+package selvpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// StatusDeleted represents the pseudo status of the resource that
+	// doesn't exist anymore. It can be used as a target status of the
+	// WaitForStatus to wait for a deletion.
+	StatusDeleted = "DELETED"
+
+	// defaultWaitInterval represents the default delay before the second
+	// status check.
+	defaultWaitInterval = 2 * time.Second
+
+	// defaultWaitMaxInterval represents the default maximum delay between
+	// status checks.
+	defaultWaitMaxInterval = 30 * time.Second
+)
+
+var (
+	errWaitNoStatuses    = errors.New("no target statuses are provided")
+	errWaitNilStatusFunc = errors.New("status function is nil")
+	errWaitNilGetFunc    = errors.New("resource get function is nil")
+)
+
+// ErrWaitNoResource is returned by the status waiters if the status request
+// succeeded but returned no resource.
+var ErrWaitNoResource = errors.New("selvpcclient: status response contains no resource")
+
+// WaitOpts represents options of the status waiters.
+type WaitOpts struct {
+	// Interval represents the delay before the second status check.
+	// Every next delay is doubled. Default interval is used if it's zero.
+	Interval time.Duration
+
+	// MaxInterval represents the upper bound of the delay between checks.
+	// Default maximum interval is used if it's zero.
+	MaxInterval time.Duration
+
+	// Timeout limits the whole waiting in addition to the context deadline.
+	// Only the context is used if it's zero.
+	Timeout time.Duration
+}
+
+// interval returns the delay before the provided check that starts from 1.
+func (opts WaitOpts) interval(check int) time.Duration {
+	delay, maxDelay := opts.Interval, opts.MaxInterval
+	if delay <= 0 {
+		delay = defaultWaitInterval
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultWaitMaxInterval
+	}
+	for i := 1; i < check && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// StatusFunc returns the current status of the waited resource.
+// The not found APIError is treated as the StatusDeleted.
+type StatusFunc func(ctx context.Context) (string, error)
+
+// ResourceGetFunc returns the current state of the waited resource and its
+// status. The nil resource without an error is treated as the
+// ErrWaitNoResource.
+type ResourceGetFunc func(ctx context.Context) (resource interface{}, status string, err error)
+
+// UnexpectedStatusError is returned by the status waiters if the resource
+// reaches a terminal status that isn't expected, for example ERROR or
+// DELETED while waiting for ACTIVE.
+type UnexpectedStatusError struct {
+	// Resource contains the name of the resource like "floatingips".
+	Resource string
+
+	// ID contains the resource ID.
+	ID string
+
+	// Status contains the reached status.
+	Status string
+
+	// Expected contains the statuses that were waited for.
+	Expected []string
+}
+
+// Error implements the error interface.
+func (e *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("selvpcclient: %s %s reached the %s status while waiting for %s",
+		e.Resource, e.ID, e.Status, strings.Join(e.Expected, ", "))
+}
+
+// WaitForStatus checks the resource status with the provided function until
+// it reaches one of the target statuses. It returns the UnexpectedStatusError
+// if the resource reaches one of the failed statuses or is deleted while
+// the StatusDeleted isn't the target.
+// Transient errors like 429 and 5xx API errors or dropped connections don't
+// stop the waiting, the status is checked again after the usual delay.
+// The context error is returned with the last seen status if the waiting is
+// timed out.
+func WaitForStatus(ctx context.Context, opts WaitOpts, resource, id string, statusFunc StatusFunc, target, failed []string) error {
+	if statusFunc == nil {
+		return fmt.Errorf("selvpcclient: %w", errWaitNilStatusFunc)
+	}
+	if len(target) == 0 {
+		return fmt.Errorf("selvpcclient: %w", errWaitNoStatuses)
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	lastStatus := ""
+	for check := 1; ; check++ {
+		status, err := statusFunc(ctx)
+		switch {
+		case IsNotFound(err):
+			status = StatusDeleted
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return waitTimeoutError(resource, id, lastStatus, target, ctxErr)
+			}
+			if !isTransientWaitError(err) {
+				return err
+			}
+			if err := sleepContext(ctx, opts.interval(check)); err != nil {
+				return waitTimeoutError(resource, id, lastStatus, target, err)
+			}
+			continue
+		}
+		lastStatus = status
+
+		if containsStatus(target, status) {
+			return nil
+		}
+		if status == StatusDeleted || containsStatus(failed, status) {
+			return &UnexpectedStatusError{
+				Resource: resource,
+				ID:       id,
+				Status:   status,
+				Expected: target,
+			}
+		}
+
+		if err := sleepContext(ctx, opts.interval(check)); err != nil {
+			return waitTimeoutError(resource, id, lastStatus, target, err)
+		}
+	}
+}
+
+// WaitForResource gets the resource with the provided function like the
+// WaitForStatus checks its status and returns the last received resource.
+// Resource packages use it for their typed WaitForStatus functions.
+func WaitForResource(ctx context.Context, opts WaitOpts, resource, id string, getFunc ResourceGetFunc, target, failed []string) (interface{}, error) {
+	if getFunc == nil {
+		return nil, fmt.Errorf("selvpcclient: %w", errWaitNilGetFunc)
+	}
+
+	var last interface{}
+	err := WaitForStatus(ctx, opts, resource, id, func(ctx context.Context) (string, error) {
+		current, status, err := getFunc(ctx)
+		if err != nil {
+			return "", err
+		}
+		if current == nil {
+			return "", fmt.Errorf("%w: %s %s", ErrWaitNoResource, resource, id)
+		}
+		last = current
+
+		return status, nil
+	}, target, failed)
+
+	return last, err
+}
+
+// waitTimeoutError wraps the context error with the last seen status.
+func waitTimeoutError(resource, id, lastStatus string, target []string, err error) error {
+	if lastStatus == "" {
+		return fmt.Errorf("selvpcclient: stopped waiting for %s %s to reach %s: %w",
+			resource, id, strings.Join(target, ", "), err)
+	}
+
+	return fmt.Errorf("selvpcclient: stopped waiting for %s %s to reach %s, last status is %s: %w",
+		resource, id, strings.Join(target, ", "), lastStatus, err)
+}
+
+// isTransientWaitError checks if the status check failed because of a
+// temporary problem and should be repeated.
+func isTransientWaitError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	return isRetryableNetworkError(err)
+}
+
+// containsStatus checks if the status is in the provided list.
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}